@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"rachao/config"
+)
+
+const configUsage = "usage: rachao [flags] config print"
+
+// runConfig implements the "config" subcommand, showing the configuration
+// the API would start with.
+func runConfig(cfg *config.Config, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return fmt.Errorf("%s", configUsage)
+	}
+	cfg.Print(os.Stdout)
+	return nil
+}
@@ -1,52 +1,130 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
 	"rachao/config"
+	"rachao/db/migrations"
 	"rachao/infra/messaging"
+	"rachao/infra/metrics"
+	"rachao/infra/migrate"
 	"rachao/infra/repositories"
+	"rachao/infra/tracing"
 	"rachao/internal/core/adapters"
 	"rachao/internal/core/usecase"
+	"syscall"
 
 	_ "github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
+// version and commit are set at build time with
+// -ldflags "-X main.version=... -X main.commit=...".
+var (
+	version = "dev"
+	commit  = "unknown"
+)
+
 func main() {
-	cfg := config.Load()
+	cfg, args, err := config.Load(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if len(args) > 0 && args[0] == "config" {
+		if err := runConfig(cfg, args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
 
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
 
-	db := config.InitDatabase(cfg.DbSource)
-	defer db.Close()
-
-	rabbitMQChannel := config.InitRabbitMQ(cfg.Messaging, cfg.MessagingChannel)
-	defer rabbitMQChannel.Close()
-
-	repoPlay := repositories.PlayRepository{DB: db}
-	repoCard := repositories.CardRepository{DB: db}
-	repoCardPlay := repositories.CardPlayRepository{DB: db}
-	repoNation := repositories.NationRepository{DB: db}
-	repoPhoto := repositories.PhotoRepository{DB: db}
-	repoPosition := repositories.PositionRepository{DB: db}
-	repoAttribute := repositories.AttributesRepository{DB: db}
-	repoOverall := repositories.OverallRepository{DB: db}
-	repoModality := repositories.ModalityRepository{DB: db}
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.TracingExporter, version)
+	if err != nil {
+		logger.Fatal("Error initializing tracing", zap.Error(err))
+	}
+
+	db, err := config.InitDatabase(context.Background(), cfg, logger)
+	if err != nil {
+		logger.Fatal("Error connecting to the database", zap.Error(err))
+	}
+	metrics.RegisterDB(db, "rachao")
+
+	if len(args) > 0 && args[0] == "migrate" {
+		err := runMigrate(db, logger, args[1:])
+		db.Close()
+		shutdownTracing(context.Background())
+		if err != nil {
+			logger.Fatal("Error running migrations", zap.Error(err))
+		}
+		return
+	}
+
+	if cfg.MigrateOnStart {
+		count, err := migrate.NewMigrator(db, migrations.FS, logger).Up(context.Background())
+		if err != nil {
+			logger.Fatal("Error running migrations", zap.Error(err))
+		}
+		logger.Info("Migrations applied", zap.Int("count", count))
+	}
+
+	rabbitMQConn, rabbitMQChannel, err := config.InitRabbitMQ(context.Background(), cfg, logger)
+	if err != nil {
+		logger.Fatal("Error connecting to RabbitMQ", zap.Error(err))
+	}
+
+	repoPlay := repositories.PlayRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoCard := repositories.CardRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoCardPlay := repositories.CardPlayRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoNation := repositories.NationRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoPhoto := repositories.PhotoRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoPosition := repositories.PositionRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoAttribute := repositories.AttributesRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoOverall := repositories.OverallRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoModality := repositories.ModalityRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoSeason := repositories.SeasonRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoMatch := repositories.MatchRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoStats := repositories.StatsRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoRating := repositories.RatingRepository{DB: db, Timeout: cfg.QueryTimeout, RebuildTimeout: cfg.RebuildTimeout}
+	repoPrediction := repositories.PredictionRepository{DB: db, Timeout: cfg.QueryTimeout}
+	repoFormation := repositories.FormationRepository{DB: db, Timeout: cfg.QueryTimeout}
 	rabbitmq := messaging.RabbitMQ{Channel: rabbitMQChannel, Exchange: cfg.MessagingChannel}
 
-	healthzUseCase := &usecase.HealthzUseCase{}
+	healthzUseCase := usecase.NewHealthzUseCase(&rabbitmq, version, commit, db, logger)
 
-	overallUseCase := usecase.NewOverallUseCase(&rabbitmq, &repoOverall, db, logger)
-	playUseCase := usecase.NewPlayUseCase(&repoPlay, db, logger)
-	cardUseCase := usecase.NewCardUseCase(&repoCard, &repoCardPlay, &repoAttribute, &rabbitmq, db, logger)
+	overallUseCase := usecase.NewOverallUseCase(&rabbitmq, &repoOverall, &repoPlay, db, logger)
+	ratingUseCase := usecase.NewRatingUseCase(&repoRating, &repoPlay, cfg.RatingWeight, db, logger)
+	playUseCase := usecase.NewPlayUseCase(&repoPlay, &repoPosition, &repoNation, &rabbitmq, ratingUseCase, cfg.MessagingChannel, db, logger)
+	cardUseCase := usecase.NewCardUseCase(&repoCard, &repoCardPlay, &repoAttribute, &rabbitmq, cfg.MessagingChannel, db, logger)
 	cardPlayUseCase := usecase.NewCardPlayUseCase(&repoCardPlay, db, logger)
 	nationUseCase := usecase.NewNationUseCase(&repoNation, db, logger)
 	photoUseCase := usecase.NewPhotoUseCase(&repoPhoto, db, logger)
 	positionUseCase := usecase.NewPositionUseCase(&repoPosition, db, logger)
-	attributesUseCase := usecase.NewAttributesUseCase(&repoAttribute, &repoPosition, db, logger)
-	modalitiesUseCase := usecase.NewModalityUseCase(&repoModality, db, logger)
+	messagingUseCase := usecase.NewMessagingaUseCase(&rabbitmq, cfg.MessagingChannel, logger)
+	recalculateUseCase := usecase.NewRecalculateUseCase(&repoCardPlay, &repoAttribute, messagingUseCase, db, logger)
+	attributesUseCase := usecase.NewAttributesUseCase(&repoAttribute, &repoPosition, recalculateUseCase, db, logger)
+	modalitiesUseCase := usecase.NewModalityUseCase(&repoModality, &repoFormation, &repoPosition, db, logger)
+	seasonUseCase := usecase.NewSeasonUseCase(&repoSeason, &repoMatch, db, logger)
+	matchUseCase := usecase.NewMatchUseCase(&repoMatch, &repoSeason, &repoModality, ratingUseCase, db, logger)
+	predictionUseCase := usecase.NewPredictionUseCase(&repoPrediction, db, logger)
+	formationUseCase := usecase.NewFormationUseCase(&repoFormation, &repoModality, ratingUseCase, db, logger)
+	statsUseCase := usecase.NewStatsUseCase(&repoStats, &repoPlay, &repoSeason, db, logger)
+
+	metricsUseCase := usecase.NewMetricsUseCase(&repoPlay, &repoCard, logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	go overallUseCase.Start()
+	go metricsUseCase.Start(ctx)
 
 	GinAdapater := adapters.NewGinAdapter(
 		healthzUseCase,
@@ -58,9 +136,36 @@ func main() {
 		positionUseCase,
 		attributesUseCase,
 		modalitiesUseCase,
+		recalculateUseCase,
+		seasonUseCase,
+		matchUseCase,
+		statsUseCase,
+		ratingUseCase,
+		predictionUseCase,
+		formationUseCase,
+		cfg.AdminToken,
+		logger,
 	)
 
-	r := GinAdapater.SetupRouter()
-	r.Run(cfg.Port)
+	srv := &http.Server{
+		Addr:    cfg.Port,
+		Handler: GinAdapater.SetupRouter(),
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		logger.Info("Shutdown signal received")
+	case err := <-serverErr:
+		logger.Error("HTTP server stopped", zap.Error(err))
+	}
+	stop()
 
+	shutdown(srv, recalculateUseCase, overallUseCase, rabbitMQConn, rabbitMQChannel, db, shutdownTracing, cfg.ShutdownTimeout, logger)
 }
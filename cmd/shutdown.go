@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"rachao/internal/core/usecase"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+// shutdown releases resources in dependency order: stop taking requests,
+// drain in-flight requests, stop the recalculation jobs and the overall
+// consumer after its current message, then close the broker and the
+// database and flush the spans recorded on the way. Every step shares the
+// same deadline so a stuck step cannot hold the process forever.
+func shutdown(srv *http.Server, recalculate *usecase.RecalculateUseCase, overall *usecase.OverallUseCase, conn *amqp.Connection, channel *amqp.Channel, db *sql.DB, shutdownTracing func(context.Context) error, timeout time.Duration, logger *zap.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("Error shutting down HTTP server", zap.Error(err))
+	} else {
+		logger.Info("HTTP server stopped")
+	}
+
+	if err := recalculate.Stop(ctx); err != nil {
+		logger.Error("Error stopping recalculation jobs", zap.Error(err))
+	} else {
+		logger.Info("Recalculation jobs stopped")
+	}
+
+	if err := overall.Stop(ctx); err != nil {
+		logger.Error("Error stopping overall consumer", zap.Error(err))
+	} else {
+		logger.Info("Overall consumer stopped")
+	}
+
+	if err := channel.Close(); err != nil {
+		logger.Error("Error closing RabbitMQ channel", zap.Error(err))
+	}
+	if err := conn.Close(); err != nil {
+		logger.Error("Error closing RabbitMQ connection", zap.Error(err))
+	}
+	logger.Info("RabbitMQ connection closed")
+
+	if err := db.Close(); err != nil {
+		logger.Error("Error closing database", zap.Error(err))
+	} else {
+		logger.Info("Database closed")
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("Error flushing traces", zap.Error(err))
+	}
+}
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "overall";
+DROP TABLE IF EXISTS "photo";
+DROP TABLE IF EXISTS "card";
+DROP TABLE IF EXISTS "attributes";
+DROP TABLE IF EXISTS "play";
+DROP TABLE IF EXISTS "modality";
+DROP TABLE IF EXISTS "position";
+DROP TABLE IF EXISTS "nation";
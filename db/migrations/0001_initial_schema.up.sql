@@ -0,0 +1,92 @@
+-- Baseline schema, equivalent to the former db/bd.sql. Written to be a no-op
+-- on databases that were created by hand from that script.
+
+CREATE TABLE IF NOT EXISTS "play" (
+  "id" uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  "name" varchar(50),
+  "id_position" integer,
+  "id_nation" integer,
+  "field" boolean,
+  "active" boolean
+);
+
+CREATE TABLE IF NOT EXISTS "card" (
+  "id" uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  "id_play" uuid,
+  "pac" integer,
+  "sho" integer,
+  "pas" integer,
+  "dri" integer,
+  "def" integer,
+  "phy" integer
+);
+
+CREATE TABLE IF NOT EXISTS "nation" (
+  "id" integer NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "name" varchar(50),
+  "acronym" varchar(3)
+);
+
+CREATE TABLE IF NOT EXISTS "photo" (
+  "id" uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  "id_play" uuid,
+  "photo" bytea
+);
+
+CREATE TABLE IF NOT EXISTS "position" (
+  "id" integer NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "name" varchar(50),
+  "acronym" varchar(3)
+);
+
+CREATE TABLE IF NOT EXISTS "attributes" (
+  "id" integer NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "id_position" integer,
+  "pac" integer,
+  "sho" integer,
+  "pas" integer,
+  "dri" integer,
+  "def" integer,
+  "phy" integer
+);
+
+CREATE TABLE IF NOT EXISTS "overall" (
+  "id" uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  "id_play" uuid,
+  "overall" integer
+);
+
+CREATE TABLE IF NOT EXISTS "modality" (
+  "id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY NOT NULL,
+  "name" varchar(50),
+  "amount_play" int,
+  "active" boolean
+);
+
+-- Foreign keys use PostgreSQL's default names so the ones db/bd.sql already
+-- created are recognised and skipped.
+DO $$
+DECLARE
+  fk text[];
+BEGIN
+  FOREACH fk SLICE 1 IN ARRAY ARRAY[
+    ['attributes', 'id_position', 'position'],
+    ['overall', 'id_play', 'play'],
+    ['photo', 'id_play', 'play'],
+    ['card', 'id_play', 'play'],
+    ['play', 'id_nation', 'nation'],
+    ['play', 'id_position', 'position']
+  ] LOOP
+    IF NOT EXISTS (
+      SELECT 1 FROM pg_constraint
+      WHERE conname = fk[1] || '_' || fk[2] || '_fkey'
+        AND conrelid = format('%I', fk[1])::regclass
+    ) THEN
+      EXECUTE format(
+        'ALTER TABLE %I ADD CONSTRAINT %I FOREIGN KEY (%I) REFERENCES %I ("id")',
+        fk[1], fk[1] || '_' || fk[2] || '_fkey', fk[2], fk[3]
+      );
+    END IF;
+  END LOOP;
+END
+$$;
@@ -0,0 +1,41 @@
+ALTER TABLE "overall" DROP CONSTRAINT IF EXISTS "overall_overall_check";
+ALTER TABLE "overall" ALTER COLUMN "overall" DROP NOT NULL;
+
+ALTER TABLE "attributes" DROP CONSTRAINT IF EXISTS "attributes_weights_check";
+ALTER TABLE "attributes"
+  ALTER COLUMN "pac" DROP NOT NULL,
+  ALTER COLUMN "sho" DROP NOT NULL,
+  ALTER COLUMN "pas" DROP NOT NULL,
+  ALTER COLUMN "dri" DROP NOT NULL,
+  ALTER COLUMN "def" DROP NOT NULL,
+  ALTER COLUMN "phy" DROP NOT NULL;
+
+ALTER TABLE "card" DROP CONSTRAINT IF EXISTS "card_stats_check";
+ALTER TABLE "card"
+  ALTER COLUMN "pac" DROP NOT NULL,
+  ALTER COLUMN "sho" DROP NOT NULL,
+  ALTER COLUMN "pas" DROP NOT NULL,
+  ALTER COLUMN "dri" DROP NOT NULL,
+  ALTER COLUMN "def" DROP NOT NULL,
+  ALTER COLUMN "phy" DROP NOT NULL;
+
+ALTER TABLE "attributes" DROP CONSTRAINT IF EXISTS "attributes_id_position_key";
+ALTER TABLE "attributes" ALTER COLUMN "id_position" DROP NOT NULL;
+
+ALTER TABLE "photo" DROP CONSTRAINT IF EXISTS "photo_id_play_key";
+ALTER TABLE "photo" ALTER COLUMN "id_play" DROP NOT NULL;
+ALTER TABLE "overall" DROP CONSTRAINT IF EXISTS "overall_id_play_key";
+ALTER TABLE "overall" ALTER COLUMN "id_play" DROP NOT NULL;
+ALTER TABLE "card" DROP CONSTRAINT IF EXISTS "card_id_play_key";
+ALTER TABLE "card" ALTER COLUMN "id_play" DROP NOT NULL;
+
+DROP INDEX IF EXISTS "play_id_nation_idx";
+DROP INDEX IF EXISTS "play_id_position_idx";
+DROP INDEX IF EXISTS "play_name_lower_key";
+
+ALTER TABLE "play"
+  ALTER COLUMN "name" DROP NOT NULL,
+  ALTER COLUMN "field" DROP NOT NULL,
+  ALTER COLUMN "field" DROP DEFAULT,
+  ALTER COLUMN "active" DROP NOT NULL,
+  ALTER COLUMN "active" DROP DEFAULT;
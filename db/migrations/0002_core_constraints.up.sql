@@ -0,0 +1,94 @@
+-- Players: a name is required and unique regardless of case.
+UPDATE "play" SET "name" = "id"::text WHERE "name" IS NULL;
+UPDATE "play" SET "field" = false WHERE "field" IS NULL;
+UPDATE "play" SET "active" = true WHERE "active" IS NULL;
+
+ALTER TABLE "play"
+  ALTER COLUMN "name" SET NOT NULL,
+  ALTER COLUMN "field" SET NOT NULL,
+  ALTER COLUMN "field" SET DEFAULT false,
+  ALTER COLUMN "active" SET NOT NULL,
+  ALTER COLUMN "active" SET DEFAULT true;
+
+-- Names that only differ in case are kept by one player, an active one if
+-- any, then the lowest id; the others get the start of their id appended so
+-- the unique index can be built. Nothing is deleted.
+UPDATE "play" p SET "name" = left(p."name", 40) || ' #' || left(p."id"::text, 8)
+FROM (
+  SELECT "id", row_number() OVER (PARTITION BY lower("name") ORDER BY "active" DESC, "id") AS n
+  FROM "play"
+) d
+WHERE d."id" = p."id" AND d.n > 1;
+
+CREATE UNIQUE INDEX "play_name_lower_key" ON "play" (lower("name"));
+CREATE INDEX "play_id_position_idx" ON "play" ("id_position");
+CREATE INDEX "play_id_nation_idx" ON "play" ("id_nation");
+
+-- One card, overall and photo per player. The tables record no write time,
+-- so of the duplicates left by concurrent writes the row with the highest id
+-- is kept and the others are deleted. The choice is arbitrary but the same on
+-- every run; the README upgrade notes warn about it. Rows without a player
+-- belong to no one and are deleted too.
+DELETE FROM "card" WHERE "id_play" IS NULL;
+DELETE FROM "overall" WHERE "id_play" IS NULL;
+DELETE FROM "photo" WHERE "id_play" IS NULL;
+DELETE FROM "card" a USING "card" b WHERE a."id_play" = b."id_play" AND a."id" < b."id";
+DELETE FROM "overall" a USING "overall" b WHERE a."id_play" = b."id_play" AND a."id" < b."id";
+DELETE FROM "photo" a USING "photo" b WHERE a."id_play" = b."id_play" AND a."id" < b."id";
+
+ALTER TABLE "card" ALTER COLUMN "id_play" SET NOT NULL;
+ALTER TABLE "card" ADD CONSTRAINT "card_id_play_key" UNIQUE ("id_play");
+ALTER TABLE "overall" ALTER COLUMN "id_play" SET NOT NULL;
+ALTER TABLE "overall" ADD CONSTRAINT "overall_id_play_key" UNIQUE ("id_play");
+ALTER TABLE "photo" ALTER COLUMN "id_play" SET NOT NULL;
+ALTER TABLE "photo" ADD CONSTRAINT "photo_id_play_key" UNIQUE ("id_play");
+
+-- One set of weights per position. Weights without a position are
+-- unreachable and deleted; of the duplicates the row with the highest id is
+-- kept, as for cards above.
+DELETE FROM "attributes" WHERE "id_position" IS NULL;
+DELETE FROM "attributes" a USING "attributes" b WHERE a."id_position" = b."id_position" AND a."id" < b."id";
+
+ALTER TABLE "attributes" ALTER COLUMN "id_position" SET NOT NULL;
+ALTER TABLE "attributes" ADD CONSTRAINT "attributes_id_position_key" UNIQUE ("id_position");
+
+-- Stat ranges. The checks are NOT VALID so rows written before validation
+-- existed do not block the upgrade; every new or updated row is checked.
+-- Missing stats become the lowest valid value and missing weights 0, so
+-- they add nothing to an overall; an overall that was never calculated is
+-- deleted like the duplicates.
+UPDATE "card" SET
+  "pac" = COALESCE("pac", 1), "sho" = COALESCE("sho", 1), "pas" = COALESCE("pas", 1),
+  "dri" = COALESCE("dri", 1), "def" = COALESCE("def", 1), "phy" = COALESCE("phy", 1)
+WHERE "pac" IS NULL OR "sho" IS NULL OR "pas" IS NULL OR "dri" IS NULL OR "def" IS NULL OR "phy" IS NULL;
+UPDATE "attributes" SET
+  "pac" = COALESCE("pac", 0), "sho" = COALESCE("sho", 0), "pas" = COALESCE("pas", 0),
+  "dri" = COALESCE("dri", 0), "def" = COALESCE("def", 0), "phy" = COALESCE("phy", 0)
+WHERE "pac" IS NULL OR "sho" IS NULL OR "pas" IS NULL OR "dri" IS NULL OR "def" IS NULL OR "phy" IS NULL;
+DELETE FROM "overall" WHERE "overall" IS NULL;
+
+ALTER TABLE "card"
+  ALTER COLUMN "pac" SET NOT NULL,
+  ALTER COLUMN "sho" SET NOT NULL,
+  ALTER COLUMN "pas" SET NOT NULL,
+  ALTER COLUMN "dri" SET NOT NULL,
+  ALTER COLUMN "def" SET NOT NULL,
+  ALTER COLUMN "phy" SET NOT NULL;
+ALTER TABLE "card" ADD CONSTRAINT "card_stats_check" CHECK (
+  "pac" BETWEEN 1 AND 99 AND "sho" BETWEEN 1 AND 99 AND "pas" BETWEEN 1 AND 99 AND
+  "dri" BETWEEN 1 AND 99 AND "def" BETWEEN 1 AND 99 AND "phy" BETWEEN 1 AND 99
+) NOT VALID;
+
+ALTER TABLE "attributes"
+  ALTER COLUMN "pac" SET NOT NULL,
+  ALTER COLUMN "sho" SET NOT NULL,
+  ALTER COLUMN "pas" SET NOT NULL,
+  ALTER COLUMN "dri" SET NOT NULL,
+  ALTER COLUMN "def" SET NOT NULL,
+  ALTER COLUMN "phy" SET NOT NULL;
+ALTER TABLE "attributes" ADD CONSTRAINT "attributes_weights_check" CHECK (
+  "pac" >= 0 AND "sho" >= 0 AND "pas" >= 0 AND "dri" >= 0 AND "def" >= 0 AND "phy" >= 0
+) NOT VALID;
+
+ALTER TABLE "overall" ALTER COLUMN "overall" SET NOT NULL;
+ALTER TABLE "overall" ADD CONSTRAINT "overall_overall_check" CHECK ("overall" BETWEEN 1 AND 99) NOT VALID;
@@ -0,0 +1,6 @@
+ALTER TABLE "modality" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "attributes" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "position" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "nation" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "card" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "play" DROP COLUMN IF EXISTS "version";
@@ -0,0 +1,8 @@
+-- Row versions for optimistic concurrency: every UPDATE bumps the version,
+-- and PUT/PATCH with If-Match only apply when it still matches.
+ALTER TABLE "play" ADD COLUMN IF NOT EXISTS "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "card" ADD COLUMN IF NOT EXISTS "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "nation" ADD COLUMN IF NOT EXISTS "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "position" ADD COLUMN IF NOT EXISTS "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "attributes" ADD COLUMN IF NOT EXISTS "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "modality" ADD COLUMN IF NOT EXISTS "version" integer NOT NULL DEFAULT 1;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "season_snapshot";
+DROP TABLE IF EXISTS "match_player";
+DROP TABLE IF EXISTS "match";
+DROP TABLE IF EXISTS "season";
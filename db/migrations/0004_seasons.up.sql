@@ -0,0 +1,55 @@
+-- Seasons bound matches in time. A closed season no longer accepts matches
+-- and keeps a snapshot of every card and overall as they were at closing.
+CREATE TABLE "season" (
+  "id" integer NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "name" varchar(50) NOT NULL,
+  "starts_on" date NOT NULL,
+  "ends_on" date NOT NULL,
+  "closed_at" timestamptz,
+  "version" integer NOT NULL DEFAULT 1,
+  CONSTRAINT "season_dates_check" CHECK ("ends_on" >= "starts_on")
+);
+CREATE UNIQUE INDEX "season_name_lower_key" ON "season" (lower("name"));
+
+-- A match is unfinished while its score is NULL.
+CREATE TABLE "match" (
+  "id" uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  "id_season" integer NOT NULL REFERENCES "season" ("id"),
+  "id_modality" integer NOT NULL REFERENCES "modality" ("id"),
+  "played_at" timestamptz NOT NULL,
+  "home_goals" integer,
+  "away_goals" integer,
+  "version" integer NOT NULL DEFAULT 1,
+  CONSTRAINT "match_score_check" CHECK (
+    ("home_goals" IS NULL AND "away_goals" IS NULL) OR ("home_goals" >= 0 AND "away_goals" >= 0)
+  )
+);
+CREATE INDEX "match_id_season_idx" ON "match" ("id_season");
+
+-- Who played for which side. overall is the player's overall when the match
+-- was registered, so later card changes do not rewrite history.
+CREATE TABLE "match_player" (
+  "id_match" uuid NOT NULL REFERENCES "match" ("id") ON DELETE CASCADE,
+  "id_play" uuid NOT NULL REFERENCES "play" ("id"),
+  "team" varchar(4) NOT NULL,
+  "overall" integer,
+  "goals" integer NOT NULL DEFAULT 0,
+  "assists" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id_match", "id_play"),
+  CONSTRAINT "match_player_team_check" CHECK ("team" IN ('home', 'away')),
+  CONSTRAINT "match_player_stats_check" CHECK ("goals" >= 0 AND "assists" >= 0)
+);
+CREATE INDEX "match_player_id_play_idx" ON "match_player" ("id_play");
+
+CREATE TABLE "season_snapshot" (
+  "id_season" integer NOT NULL REFERENCES "season" ("id") ON DELETE CASCADE,
+  "id_play" uuid NOT NULL REFERENCES "play" ("id"),
+  "pac" integer NOT NULL,
+  "sho" integer NOT NULL,
+  "pas" integer NOT NULL,
+  "dri" integer NOT NULL,
+  "def" integer NOT NULL,
+  "phy" integer NOT NULL,
+  "overall" integer,
+  PRIMARY KEY ("id_season", "id_play")
+);
@@ -0,0 +1,12 @@
+-- Rating of each player after each finished match. The table is derived
+-- from match results and rebuilt from scratch whenever a result changes, so
+-- rows are in chronological order of id.
+CREATE TABLE "rating_history" (
+  "id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "id_play" uuid NOT NULL REFERENCES "play" ("id"),
+  "id_match" uuid NOT NULL REFERENCES "match" ("id") ON DELETE CASCADE,
+  "played_at" timestamptz NOT NULL,
+  "rating_before" double precision NOT NULL,
+  "rating_after" double precision NOT NULL,
+  CONSTRAINT "rating_history_id_play_id_match_key" UNIQUE ("id_play", "id_match")
+);
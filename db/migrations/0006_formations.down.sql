@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "formation_line";
+DROP TABLE IF EXISTS "formation";
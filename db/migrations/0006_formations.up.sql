@@ -0,0 +1,50 @@
+-- Formation templates of a modality, e.g. 1-2-2 for five a side.
+CREATE TABLE "formation" (
+  "id" integer NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "id_modality" integer NOT NULL REFERENCES "modality" ("id"),
+  "name" varchar(20) NOT NULL
+);
+CREATE UNIQUE INDEX "formation_id_modality_name_lower_key" ON "formation" ("id_modality", lower("name"));
+
+-- The lines of a formation from back to front, each with the position it
+-- takes and how many players it needs. How far apart two lines are drives
+-- the penalty of playing out of position.
+CREATE TABLE "formation_line" (
+  "id_formation" integer NOT NULL REFERENCES "formation" ("id") ON DELETE CASCADE,
+  "line" integer NOT NULL,
+  "id_position" integer NOT NULL REFERENCES "position" ("id"),
+  "slots" integer NOT NULL,
+  PRIMARY KEY ("id_formation", "line"),
+  CONSTRAINT "formation_line_id_formation_id_position_key" UNIQUE ("id_formation", "id_position"),
+  CONSTRAINT "formation_line_slots_check" CHECK ("slots" > 0)
+);
+
+-- Default templates for the modalities already registered: 1-2-2 for five a
+-- side and 1-3-3 for seven a side, built from the positions whose acronyms
+-- are GOL, ZAG and ATA. A template is skipped when one of its positions does
+-- not exist; modalities created later get theirs from ModalityUseCase.Create,
+-- and modalities of other sizes through POST /formation.
+WITH "template" ("amount_play", "name", "line", "acronym", "slots") AS (
+  VALUES
+    (5, '1-2-2', 0, 'GOL', 1), (5, '1-2-2', 1, 'ZAG', 2), (5, '1-2-2', 2, 'ATA', 2),
+    (7, '1-3-3', 0, 'GOL', 1), (7, '1-3-3', 1, 'ZAG', 3), (7, '1-3-3', 2, 'ATA', 3)
+), "template_line" AS (
+  SELECT t.*, (SELECT min(p."id") FROM "position" p WHERE upper(p."acronym") = t."acronym") AS "id_position"
+  FROM "template" t
+), "complete" AS (
+  SELECT "amount_play", "name"
+  FROM "template_line"
+  GROUP BY "amount_play", "name"
+  HAVING bool_and("id_position" IS NOT NULL)
+), "seeded" AS (
+  INSERT INTO "formation" ("id_modality", "name")
+  SELECT m."id", c."name"
+  FROM "modality" m
+  JOIN "complete" c ON c."amount_play" = m."amount_play"
+  RETURNING "id", "id_modality", "name"
+)
+INSERT INTO "formation_line" ("id_formation", "line", "id_position", "slots")
+SELECT s."id", l."line", l."id_position", l."slots"
+FROM "seeded" s
+JOIN "modality" m ON m."id" = s."id_modality"
+JOIN "template_line" l ON l."amount_play" = m."amount_play" AND l."name" = s."name";
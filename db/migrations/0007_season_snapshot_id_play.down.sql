@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "season_snapshot_id_play_idx";
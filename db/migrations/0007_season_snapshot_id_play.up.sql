@@ -0,0 +1,4 @@
+-- Purging a player deletes their snapshots, and the primary key of
+-- season_snapshot starts with id_season, so the lookup by id_play needs its
+-- own index.
+CREATE INDEX "season_snapshot_id_play_idx" ON "season_snapshot" ("id_play");
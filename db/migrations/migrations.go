@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned schema scripts. Files are named
+// <version>_<name>.up.sql / <version>_<name>.down.sql and are applied in
+// version order by infra/migrate.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
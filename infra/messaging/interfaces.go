@@ -1,6 +1,10 @@
 package messaging
 
+import "context"
+
 type MessagePublisherInterface interface {
-	Publish(exchange, routingKey string, body []byte) error
-	Consumer(handler func(string), exchange string) error
+	Publish(ctx context.Context, exchange, routingKey string, body []byte) error
+	Consumer(handler func(ctx context.Context, body string) error, queue string) error
+	Check() error
+	Stop(ctx context.Context) error
 }
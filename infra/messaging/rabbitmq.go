@@ -1,14 +1,46 @@
 package messaging
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"rachao/infra/logging"
+	"rachao/infra/metrics"
+	"rachao/infra/tracing"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrRejected marks a handler error as permanent: the message can never be
+// processed and goes to the dead letter queue instead of being retried.
+var ErrRejected = errors.New("message rejected")
+
+// MaxDeliveries is how many times a message whose handler failed for any
+// other reason is attempted before it is dead-lettered.
+const MaxDeliveries = 5
+
+// retryHeader counts the attempts already made on a redelivered message.
+const retryHeader = "x-retries"
+
 type RabbitMQ struct {
 	Channel  *amqp.Channel
 	Exchange string
+
+	mu        sync.Mutex
+	consumers []string
+	inFlight  sync.WaitGroup
+	stopping  atomic.Bool
+	// expected counts the consumers asked for, attached the ones receiving
+	// deliveries; a consumer that failed to start stays expected.
+	expected atomic.Int32
+	attached atomic.Int32
 }
 
 func (p *RabbitMQ) ensureChannel() error {
@@ -21,34 +53,66 @@ func (p *RabbitMQ) ensureChannel() error {
 	return nil
 }
 
-func (p *RabbitMQ) Publish(exchange, routingKey string, body []byte) error {
+// Publish sends body carrying the trace context of ctx in the message headers
+// and its request ID as correlation ID, so the consumer's span and logs are
+// tied to the request that caused the message.
+func (p *RabbitMQ) Publish(ctx context.Context, exchange, routingKey string, body []byte) error {
+	ctx, span := tracing.Tracer().Start(ctx, "publish "+exchange,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", exchange),
+			attribute.String("messaging.rabbitmq.destination.routing_key", routingKey),
+		),
+	)
+	defer span.End()
+
 	if err := p.ensureChannel(); err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
-	if p.Channel == nil {
-		return fmt.Errorf("RabbitMQ channel is not initialized")
-	}
-	err := p.Channel.Publish(
+	err := p.Channel.PublishWithContext(
+		ctx,
 		exchange,
 		routingKey,
 		false,
 		false,
 		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
+			ContentType:   "application/json",
+			Timestamp:     time.Now(),
+			CorrelationId: logging.RequestID(ctx),
+			Headers:       injectTrace(ctx, nil),
+			Body:          body,
 		},
 	)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		metrics.MessagesPublished.WithLabelValues(exchange, "error").Inc()
 		return err
 	}
+	metrics.MessagesPublished.WithLabelValues(exchange, "ok").Inc()
 	return nil
 }
 
-func (c *RabbitMQ) Consumer(handler func(string), exchange string) error {
+// Consumer handles every delivery of queue in a span that continues the
+// trace found in the message headers. Messages the handler rejects with
+// ErrRejected, or that make it panic, are moved to "<queue>.dead"; other
+// failures are retried through "<queue>.retry" up to MaxDeliveries times
+// before they are moved there too.
+func (c *RabbitMQ) Consumer(handler func(ctx context.Context, body string) error, queue string) error {
+	tag := "rachao-" + queue
+	c.expected.Add(1)
+	if err := c.declareDeadLetter(queue); err != nil {
+		return err
+	}
+	if err := c.declareRetry(queue); err != nil {
+		return err
+	}
 	msgs, err := c.Channel.Consume(
-		"overall",
-		"",
-		true,
+		queue,
+		tag,
+		false,
 		false,
 		false,
 		false,
@@ -58,11 +122,253 @@ func (c *RabbitMQ) Consumer(handler func(string), exchange string) error {
 		return err
 	}
 
+	c.mu.Lock()
+	c.consumers = append(c.consumers, tag)
+	c.mu.Unlock()
+
+	c.inFlight.Add(1)
+	c.attached.Add(1)
 	go func() {
+		defer c.inFlight.Done()
+		// The delivery channel is closed when the consumer is cancelled or
+		// the broker connection drops.
+		defer c.attached.Add(-1)
 		for msg := range msgs {
-			handler(string(msg.Body))
+			// Deliveries already buffered when Stop was called go back to
+			// the queue for another instance instead of being processed.
+			if c.stopping.Load() {
+				msg.Nack(false, true)
+				metrics.MessagesConsumed.WithLabelValues(queue, "requeue").Inc()
+				continue
+			}
+			err := c.handle(handler, queue, msg)
+			if !msg.Timestamp.IsZero() {
+				metrics.ConsumeLag.WithLabelValues(queue).Observe(time.Since(msg.Timestamp).Seconds())
+			}
+			switch outcome, attempt := settle(err, msg.Headers); outcome {
+			case outcomeRetry:
+				c.retry(queue, msg, attempt)
+				metrics.MessagesConsumed.WithLabelValues(queue, "retry").Inc()
+			case outcomeDeadLetter:
+				c.deadLetter(queue, msg, err)
+				metrics.MessagesConsumed.WithLabelValues(queue, "dead_letter").Inc()
+			default:
+				msg.Ack(false)
+				metrics.MessagesConsumed.WithLabelValues(queue, "ack").Inc()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// handle runs handler for msg in its own span. A panicking handler is
+// recovered and reported as ErrRejected, since running the same body again
+// would panic again.
+func (c *RabbitMQ) handle(handler func(ctx context.Context, body string) error, queue string, msg amqp.Delivery) (err error) {
+	ctx := extractTrace(context.Background(), msg.Headers)
+	if msg.CorrelationId != "" {
+		ctx = logging.WithRequestID(ctx, msg.CorrelationId)
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "process "+queue,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.source.name", queue),
+			attribute.String("messaging.rabbitmq.destination.routing_key", msg.RoutingKey),
+		),
+	)
+	defer span.End()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: handler panicked: %v", ErrRejected, r)
+		}
+		if err != nil {
+			span.RecordError(err, trace.WithStackTrace(true))
+			span.SetStatus(codes.Error, err.Error())
 		}
 	}()
 
+	return handler(ctx, string(msg.Body))
+}
+
+// Check reports whether the channel is open and every consumer asked for is
+// receiving deliveries, including one whose start failed. The service always
+// consumes, so it is not ready before its first consumer was registered.
+func (c *RabbitMQ) Check() error {
+	expected := int(c.expected.Load())
+	if expected == 0 {
+		return fmt.Errorf("no consumer registered yet")
+	}
+	if err := c.ensureChannel(); err != nil {
+		return err
+	}
+	if attached := int(c.attached.Load()); attached < expected {
+		return fmt.Errorf("%d of %d consumers attached", attached, expected)
+	}
+	if c.stopping.Load() {
+		return fmt.Errorf("consumers stopped")
+	}
 	return nil
 }
+
+// Stop cancels every consumer and waits, until ctx is done, for the message
+// being handled to be acknowledged.
+func (c *RabbitMQ) Stop(ctx context.Context) error {
+	c.stopping.Store(true)
+
+	c.mu.Lock()
+	consumers := c.consumers
+	c.consumers = nil
+	c.mu.Unlock()
+
+	for _, tag := range consumers {
+		if err := c.Channel.Cancel(tag, false); err != nil {
+			return err
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// declareDeadLetter declares the durable "<queue>.dead" queue and binds it
+// to the service exchange under the same routing key deadLetter uses.
+func (c *RabbitMQ) declareDeadLetter(queue string) error {
+	dead := queue + ".dead"
+	if _, err := c.Channel.QueueDeclare(dead, true, false, false, false, nil); err != nil {
+		return err
+	}
+	return c.Channel.QueueBind(dead, dead, c.Exchange, false, nil)
+}
+
+// declareRetry declares the durable "<queue>.retry" queue. It has no
+// consumer: messages wait there until their expiration and are then
+// dead-lettered back to queue through the default exchange.
+func (c *RabbitMQ) declareRetry(queue string) error {
+	_, err := c.Channel.QueueDeclare(queue+".retry", true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": queue,
+	})
+	return err
+}
+
+// outcome is what the consumer does with a delivery once it was handled.
+type outcome int
+
+const (
+	outcomeAck outcome = iota
+	outcomeRetry
+	outcomeDeadLetter
+)
+
+// settle decides the outcome of a delivery from the handler's error and the
+// retry header it carried. For outcomeRetry it also returns the attempt
+// number the retried copy must carry.
+func settle(err error, headers amqp.Table) (outcome, int) {
+	if err == nil {
+		return outcomeAck, 0
+	}
+	attempt := retries(headers) + 1
+	if errors.Is(err, ErrRejected) || attempt >= MaxDeliveries {
+		return outcomeDeadLetter, 0
+	}
+	return outcomeRetry, attempt
+}
+
+// retries reads the attempts already made from the retry header. Tables
+// decode integers with the width their publisher used, so any integer type
+// is accepted; a missing, negative or non-integer value counts as none.
+func retries(headers amqp.Table) int {
+	var count int64
+	switch n := headers[retryHeader].(type) {
+	case int:
+		count = int64(n)
+	case int8:
+		count = int64(n)
+	case int16:
+		count = int64(n)
+	case int32:
+		count = int64(n)
+	case int64:
+		count = n
+	case uint8:
+		count = int64(n)
+	case uint16:
+		count = int64(n)
+	case uint32:
+		count = int64(n)
+	case uint64:
+		count = int64(min(n, MaxDeliveries))
+	}
+	return int(max(0, min(count, MaxDeliveries)))
+}
+
+// retry parks a message that failed for a possibly transient reason in
+// "<queue>.retry" carrying attempt in its header and acknowledges it at once,
+// so the consumer moves on to the next delivery. The broker hands it back to
+// queue once it expires, a wait that grows with the attempts so a database
+// outage is not hammered. Should the publish fail, the broker requeues the
+// message.
+func (c *RabbitMQ) retry(queue string, msg amqp.Delivery, attempt int) {
+	headers := amqp.Table{}
+	for key, value := range msg.Headers {
+		headers[key] = value
+	}
+	headers[retryHeader] = int32(attempt)
+	err := c.Channel.Publish("", queue+".retry", false, false, amqp.Publishing{
+		ContentType:   msg.ContentType,
+		CorrelationId: msg.CorrelationId,
+		Timestamp:     msg.Timestamp,
+		Expiration:    retryDelay(attempt),
+		Body:          msg.Body,
+		Headers:       headers,
+	})
+	if err != nil {
+		msg.Nack(false, true)
+		return
+	}
+	msg.Ack(false)
+}
+
+// retryDelay is the expiration, in milliseconds as AMQP expects it, of a
+// message parked for its attempt-th retry.
+func retryDelay(attempt int) string {
+	return strconv.FormatInt((time.Duration(attempt) * time.Second).Milliseconds(), 10)
+}
+
+// deadLetter republishes a message that cannot be processed to the
+// "<queue>.dead" queue, keeping the failure reason in the headers, so it can
+// be inspected and replayed instead of being lost. The message is requeued
+// rather than acknowledged if the publish fails.
+func (c *RabbitMQ) deadLetter(queue string, msg amqp.Delivery, reason error) {
+	err := c.Channel.Publish(
+		c.Exchange,
+		queue+".dead",
+		false,
+		false,
+		amqp.Publishing{
+			ContentType:   msg.ContentType,
+			CorrelationId: msg.CorrelationId,
+			Body:          msg.Body,
+			Headers: injectTrace(extractTrace(context.Background(), msg.Headers), amqp.Table{
+				"x-error":                reason.Error(),
+				"x-original-routing-key": msg.RoutingKey,
+			}),
+		},
+	)
+	if err != nil {
+		msg.Nack(false, true)
+		return
+	}
+	msg.Ack(false)
+}
@@ -0,0 +1,85 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestSettle(t *testing.T) {
+	transient := errors.New("connection refused")
+	rejected := fmt.Errorf("%w: bad body", ErrRejected)
+
+	tests := []struct {
+		name        string
+		err         error
+		headers     amqp.Table
+		want        outcome
+		wantAttempt int
+	}{
+		{"handled", nil, nil, outcomeAck, 0},
+		{"handled after retries", nil, amqp.Table{retryHeader: int32(3)}, outcomeAck, 0},
+		{"rejected", rejected, nil, outcomeDeadLetter, 0},
+		{"rejected on a retry", rejected, amqp.Table{retryHeader: int32(1)}, outcomeDeadLetter, 0},
+		{"first failure without headers", transient, nil, outcomeRetry, 1},
+		{"first failure with other headers", transient, amqp.Table{"traceparent": "00-abc"}, outcomeRetry, 1},
+		{"int32 count", transient, amqp.Table{retryHeader: int32(2)}, outcomeRetry, 3},
+		{"int64 count", transient, amqp.Table{retryHeader: int64(2)}, outcomeRetry, 3},
+		{"int16 count", transient, amqp.Table{retryHeader: int16(2)}, outcomeRetry, 3},
+		{"uint8 count", transient, amqp.Table{retryHeader: uint8(2)}, outcomeRetry, 3},
+		{"last retry", transient, amqp.Table{retryHeader: int32(MaxDeliveries - 2)}, outcomeRetry, MaxDeliveries - 1},
+		{"retries exhausted", transient, amqp.Table{retryHeader: int32(MaxDeliveries - 1)}, outcomeDeadLetter, 0},
+		{"retries exhausted as int64", transient, amqp.Table{retryHeader: int64(MaxDeliveries - 1)}, outcomeDeadLetter, 0},
+		{"huge count", transient, amqp.Table{retryHeader: int64(1 << 40)}, outcomeDeadLetter, 0},
+		{"huge unsigned count", transient, amqp.Table{retryHeader: uint64(1 << 63)}, outcomeDeadLetter, 0},
+		{"negative count", transient, amqp.Table{retryHeader: int32(-7)}, outcomeRetry, 1},
+		{"non integer count", transient, amqp.Table{retryHeader: "3"}, outcomeRetry, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, attempt := settle(tt.err, tt.headers)
+			if got != tt.want || attempt != tt.wantAttempt {
+				t.Errorf("settle = %d, %d; want %d, %d", got, attempt, tt.want, tt.wantAttempt)
+			}
+		})
+	}
+}
+
+func TestHandleRecoversPanics(t *testing.T) {
+	var c RabbitMQ
+	err := c.handle(func(ctx context.Context, body string) error {
+		var card map[string]int
+		card[body]++
+		return nil
+	}, "overall", amqp.Delivery{Body: []byte("card")})
+
+	if !errors.Is(err, ErrRejected) {
+		t.Fatalf("handle = %v, want ErrRejected", err)
+	}
+	if outcome, _ := settle(err, amqp.Table{}); outcome != outcomeDeadLetter {
+		t.Errorf("a panicking message settles as %d, want dead letter", outcome)
+	}
+}
+
+func TestHandleReturnsHandlerError(t *testing.T) {
+	var c RabbitMQ
+	want := errors.New("database unavailable")
+	err := c.handle(func(ctx context.Context, body string) error { return want }, "overall", amqp.Delivery{})
+	if err != want {
+		t.Errorf("handle = %v, want %v", err, want)
+	}
+}
+
+func TestCheckWaitsForConsumers(t *testing.T) {
+	var c RabbitMQ
+	if err := c.Check(); err == nil || err.Error() != "no consumer registered yet" {
+		t.Errorf("Check before any consumer = %v, want not ready", err)
+	}
+	c.expected.Add(1)
+	if err := c.Check(); err == nil || err.Error() != "RabbitMQ channel is nil" {
+		t.Errorf("Check with a consumer and no channel = %v, want the channel error", err)
+	}
+}
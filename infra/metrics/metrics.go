@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric exported by the API. A dedicated registry
+// keeps the output free of metrics registered by dependencies.
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RegisterDB exports the connection pool statistics (open, in use, idle,
+// wait count and duration) of db, read from db.Stats() on every scrape.
+func RegisterDB(db *sql.DB, name string) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+const namespace = "rachao"
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests handled, by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time spent handling HTTP requests, by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Time spent in repository calls, by repository and operation.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"repository", "operation"})
+
+	MessagesPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "amqp_published_total",
+		Help:      "Messages published to RabbitMQ, by exchange and result (ok, error).",
+	}, []string{"exchange", "result"})
+
+	MessagesConsumed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "amqp_consumed_total",
+		Help:      "Messages consumed from RabbitMQ, by queue and result (ack, retry, dead_letter, requeue).",
+	}, []string{"queue", "result"})
+
+	ConsumeLag = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "amqp_consume_lag_seconds",
+		Help:      "Time between a message being published and its handling finishing, by queue.",
+		Buckets:   []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300},
+	}, []string{"queue"})
+
+	ActivePlayers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "players_active",
+		Help:      "Players currently active.",
+	})
+
+	CardsWithoutOverall = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cards_without_overall",
+		Help:      "Cards whose overall was not calculated yet.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		HTTPRequests,
+		HTTPDuration,
+		QueryDuration,
+		MessagesPublished,
+		MessagesConsumed,
+		ConsumeLag,
+		ActivePlayers,
+		CardsWithoutOverall,
+	)
+}
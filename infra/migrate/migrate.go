@@ -0,0 +1,328 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// lockID is the pg_advisory_lock key that keeps two instances starting at
+// the same time from applying the same migration twice.
+const lockID = 727274
+
+const createMigrationsTableQuery = `CREATE TABLE IF NOT EXISTS schema_migrations (
+  version bigint PRIMARY KEY,
+  name varchar(255) NOT NULL,
+  checksum char(64) NOT NULL,
+  applied_at timestamptz NOT NULL DEFAULT now()
+);`
+
+const getAppliedMigrationsQuery = `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version;`
+
+const insertMigrationQuery = `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3);`
+
+const deleteMigrationQuery = `DELETE FROM schema_migrations WHERE version = $1;`
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	DB     *sql.DB
+	FS     fs.FS
+	Logger *zap.Logger
+}
+
+func NewMigrator(db *sql.DB, fsys fs.FS, logger *zap.Logger) *Migrator {
+	return &Migrator{
+		DB:     db,
+		FS:     fsys,
+		Logger: logger,
+	}
+}
+
+// Load reads and orders the migrations found in the file system. Every
+// migration needs an up script; the down script is optional. A .sql file
+// that does not follow the naming scheme, or a second script for the same
+// version and direction, is an error rather than being skipped.
+func (m *Migrator) Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(m.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("migration %s: name must be <version>_<name>.up.sql or <version>_<name>.down.sql", entry.Name())
+		}
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(m.FS, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, matches[2])
+		}
+		if (matches[3] == "up" && migration.Up != "") || (matches[3] == "down" && migration.Down != "") {
+			return nil, fmt.Errorf("migration %d has two %s scripts", version, matches[3])
+		}
+		if matches[3] == "up" {
+			sum := sha256.Sum256(content)
+			migration.Up = string(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Up applies every pending migration in order, each in its own transaction,
+// after checking that the already applied ones were not edited.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	migrations, err := m.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	conn, unlock, err := m.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if err := verify(migrations, applied); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		m.Logger.Info("Applying migration", zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+		err := m.inTx(ctx, conn, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, insertMigrationQuery, migration.Version, migration.Name, migration.Checksum)
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Down reverts the latest applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	migrations, err := m.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	conn, unlock, err := m.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if err := verify(migrations, applied); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return count, fmt.Errorf("migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+		m.Logger.Info("Reverting migration", zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+		err := m.inTx(ctx, conn, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, deleteMigrationQuery, migration.Version)
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	migrations, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(migrations))
+	for _, migration := range migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) lock(ctx context.Context) (*sql.Conn, func(), error) {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1);`, lockID); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if _, err := conn.ExecContext(ctx, createMigrationsTableQuery); err != nil {
+		conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1);`, lockID)
+		conn.Close()
+		return nil, nil, err
+	}
+
+	unlock := func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1);`, lockID); err != nil {
+			m.Logger.Error("Error releasing migration lock", zap.Error(err))
+		}
+		conn.Close()
+	}
+	return conn, unlock, nil
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, getAppliedMigrationsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var row appliedMigration
+		if err := rows.Scan(&row.Version, &row.Name, &row.Checksum, &row.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[row.Version] = row
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+func (m *Migrator) inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// verify refuses to go on when an applied migration was edited or removed
+// from the source tree, since the database no longer matches the scripts.
+func verify(migrations []Migration, applied map[int64]appliedMigration) error {
+	known := make(map[int64]Migration, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+	}
+	for version, row := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return fmt.Errorf("applied migration %d_%s is missing from the source tree", version, row.Name)
+		}
+		if migration.Checksum != row.Checksum {
+			return fmt.Errorf("checksum mismatch for migration %d_%s: applied %s, found %s", version, row.Name, row.Checksum, migration.Checksum)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"rachao/db/migrations"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func script(sql string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(sql)}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_seasons.up.sql":        script("CREATE TABLE season ();"),
+		"0001_initial.up.sql":        script("CREATE TABLE play ();"),
+		"0001_initial.down.sql":      script("DROP TABLE play;"),
+		"0010_no_down_script.up.sql": script("SELECT 1;"),
+		"migrations.go":              script("package migrations"),
+		"README.md":                  script("notes"),
+		"archive/0003_old.up.sql":    script("SELECT 1;"),
+	}
+
+	migrations, err := NewMigrator(nil, fsys, nil).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		version int64
+		name    string
+		down    bool
+	}{
+		{1, "initial", true},
+		{2, "seasons", false},
+		{10, "no_down_script", false},
+	}
+	if len(migrations) != len(want) {
+		t.Fatalf("got %d migrations, want %d: %+v", len(migrations), len(want), migrations)
+	}
+	for i, w := range want {
+		m := migrations[i]
+		if m.Version != w.version || m.Name != w.name || (m.Down != "") != w.down {
+			t.Errorf("migration %d = %d_%s (down %t), want %d_%s (down %t)", i, m.Version, m.Name, m.Down != "", w.version, w.name, w.down)
+		}
+	}
+	if migrations[0].Up != "CREATE TABLE play ();" || migrations[0].Down != "DROP TABLE play;" {
+		t.Errorf("scripts of 0001 = %q / %q", migrations[0].Up, migrations[0].Down)
+	}
+	sum := sha256.Sum256([]byte("CREATE TABLE play ();"))
+	if migrations[0].Checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("checksum = %q, want the sha256 of the up script", migrations[0].Checksum)
+	}
+}
+
+func TestLoadShippedMigrations(t *testing.T) {
+	loaded, err := NewMigrator(nil, migrations.FS, nil).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range loaded {
+		if m.Version != int64(i+1) || m.Down == "" {
+			t.Errorf("migration %d_%s: want consecutive versions with down scripts", m.Version, m.Name)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{
+			name: "no direction",
+			fsys: fstest.MapFS{"0001_initial.sql": script("SELECT 1;")},
+			want: "0001_initial.sql: name must be",
+		},
+		{
+			name: "no version",
+			fsys: fstest.MapFS{"initial.up.sql": script("SELECT 1;")},
+			want: "initial.up.sql: name must be",
+		},
+		{
+			name: "name with a dash",
+			fsys: fstest.MapFS{"0001_initial-schema.up.sql": script("SELECT 1;")},
+			want: "0001_initial-schema.up.sql: name must be",
+		},
+		{
+			name: "down script without up script",
+			fsys: fstest.MapFS{
+				"0001_initial.up.sql":   script("SELECT 1;"),
+				"0002_seasons.down.sql": script("SELECT 1;"),
+			},
+			want: "migration 2_seasons has no up script",
+		},
+		{
+			name: "same version with two names",
+			fsys: fstest.MapFS{
+				"0001_initial.up.sql": script("SELECT 1;"),
+				"0001_seasons.up.sql": script("SELECT 2;"),
+			},
+			want: "migration 1 has two names: initial and seasons",
+		},
+		{
+			name: "same version written twice",
+			fsys: fstest.MapFS{
+				"0001_initial.up.sql": script("SELECT 1;"),
+				"1_initial.up.sql":    script("SELECT 2;"),
+			},
+			want: "migration 1 has two up scripts",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := NewMigrator(nil, tt.fsys, nil).Load()
+			if err == nil {
+				t.Fatalf("Load returned %+v, want an error", migrations)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "initial", Checksum: "aaa"},
+		{Version: 2, Name: "seasons", Checksum: "bbb"},
+	}
+
+	tests := []struct {
+		name    string
+		applied map[int64]appliedMigration
+		want    string
+	}{
+		{
+			name:    "nothing applied",
+			applied: map[int64]appliedMigration{},
+		},
+		{
+			name: "some applied, rest pending",
+			applied: map[int64]appliedMigration{
+				1: {Version: 1, Name: "initial", Checksum: "aaa"},
+			},
+		},
+		{
+			name: "applied script was edited",
+			applied: map[int64]appliedMigration{
+				1: {Version: 1, Name: "initial", Checksum: "aaa"},
+				2: {Version: 2, Name: "seasons", Checksum: "old"},
+			},
+			want: "checksum mismatch for migration 2_seasons: applied old, found bbb",
+		},
+		{
+			name: "applied migration removed from disk",
+			applied: map[int64]appliedMigration{
+				1: {Version: 1, Name: "initial", Checksum: "aaa"},
+				3: {Version: 3, Name: "ratings", Checksum: "ccc"},
+			},
+			want: "applied migration 3_ratings is missing from the source tree",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verify(migrations, tt.applied)
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("verify = %v, want nil", err)
+			case tt.want != "" && (err == nil || err.Error() != tt.want):
+				t.Errorf("verify = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
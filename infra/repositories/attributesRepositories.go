@@ -1,93 +1,112 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"rachao/internal/core/domain"
+	"time"
 )
 
 type AttributesRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
 }
 
-const GetByIDPositionQuery = `SELECT * FROM attributes WHERE id_position = $1;`
+// attributesColumns is the projection scanned by scanAttributes, in the
+// same order.
+const attributesColumns = `id, id_position, pac, sho, pas, dri, def, phy, version`
 
-func (repo *AttributesRepository) GetByIDPosition(idPosition int) (domain.Attributes, error) {
-	row := repo.DB.QueryRow(GetByIDPositionQuery, idPosition)
+func scanAttributes(row rowScanner) (domain.Attributes, error) {
 	var attributes domain.Attributes
-	if err := row.Scan(&attributes.ID, &attributes.IDPosition, &attributes.PAC, &attributes.SHO, &attributes.PAS, &attributes.DRI, &attributes.DEF, &attributes.PHY); err != nil {
-		if err == sql.ErrNoRows {
-			return attributes, nil
-		}
-		return attributes, err
+	err := row.Scan(&attributes.ID, &attributes.IDPosition, &attributes.PAC, &attributes.SHO, &attributes.PAS, &attributes.DRI, &attributes.DEF, &attributes.PHY, &attributes.Version)
+	return attributes, err
+}
+
+const GetByIDPositionQuery = `SELECT ` + attributesColumns + ` FROM attributes WHERE id_position = $1;`
+
+func (repo *AttributesRepository) GetByIDPosition(ctx context.Context, idPosition int) (domain.Attributes, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	attributes, err := scanAttributes(repo.DB.QueryRowContext(ctx, GetByIDPositionQuery, idPosition))
+	if err != nil {
+		return attributes, notFoundOnNoRows(err, "attributes for position %d not found", idPosition)
 	}
 	return attributes, nil
 }
 
-const GetByIDAttributesQuery = `SELECT * FROM attributes WHERE id = $1;`
+const GetByIDAttributesQuery = `SELECT ` + attributesColumns + ` FROM attributes WHERE id = $1;`
 
-func (repo *AttributesRepository) GetByIDAttributes(id int) (domain.Attributes, error) {
-	row := repo.DB.QueryRow(GetByIDAttributesQuery, id)
-	var attributes domain.Attributes
-	if err := row.Scan(&attributes.ID, &attributes.IDPosition, &attributes.PAC, &attributes.SHO, &attributes.PAS, &attributes.DRI, &attributes.DEF, &attributes.PHY); err != nil {
-		if err == sql.ErrNoRows {
-			return attributes, nil
-		}
-		return attributes, err
+func (repo *AttributesRepository) GetByIDAttributes(ctx context.Context, id int) (domain.Attributes, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	attributes, err := scanAttributes(repo.DB.QueryRowContext(ctx, GetByIDAttributesQuery, id))
+	if err != nil {
+		return attributes, notFoundOnNoRows(err, "attributes %d not found", id)
 	}
 	return attributes, nil
 }
 
 const CreateAttributesQuery = `INSERT INTO attributes (id_position, pac, sho, pas, dri, def, phy) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`
 
-func (repo *AttributesRepository) Create(attributes domain.AttributesRequest) (int, error) {
+func (repo *AttributesRepository) Create(ctx context.Context, attributes domain.AttributesRequest) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
 	var id int
-	err := repo.DB.QueryRow(CreateAttributesQuery, attributes.IDPosition, attributes.PAC, attributes.SHO, attributes.PAS, attributes.DRI, attributes.DEF, attributes.PHY).Scan(&id)
+	err := repo.DB.QueryRowContext(ctx, CreateAttributesQuery, attributes.IDPosition, attributes.PAC, attributes.SHO, attributes.PAS, attributes.DRI, attributes.DEF, attributes.PHY).Scan(&id)
 	if err != nil {
-		return 0, err
+		return 0, conflictOnUniqueViolation(invalidOnDataError(err), "attributes for position %d already exist", attributes.IDPosition)
 	}
 	return id, nil
 }
 
-const GetAllAttributesQuery = `SELECT * FROM attributes ORDER BY id DESC;`
+const GetAllAttributesQuery = `SELECT ` + attributesColumns + ` FROM attributes ORDER BY id DESC;`
+
+func (repo *AttributesRepository) GetAll(ctx context.Context) ([]domain.Attributes, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
 
-func (repo *AttributesRepository) GetAll() ([]domain.Attributes, error) {
-	rows, err := repo.DB.Query(GetAllAttributesQuery)
+	rows, err := repo.DB.QueryContext(ctx, GetAllAttributesQuery)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var attributes []domain.Attributes
-	for rows.Next() {
-		var attribute domain.Attributes
-		if err := rows.Scan(&attribute.ID, &attribute.IDPosition, &attribute.PAC, &attribute.SHO, &attribute.PAS, &attribute.DRI, &attribute.DEF, &attribute.PHY); err != nil {
-			return nil, err
-		}
-		attributes = append(attributes, attribute)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return attributes, nil
+	return scanAll(rows, scanAttributes)
 }
 
-const UpdateAttributesQuery = `UPDATE attributes SET id_position = $1, pac = $2, sho = $3, pas = $4, dri = $5, def = $6, phy = $7 WHERE id = $8;`
+const UpdateAttributesQuery = `UPDATE attributes SET id_position = $1, pac = $2, sho = $3, pas = $4, dri = $5, def = $6, phy = $7, version = version + 1
+  WHERE id = $8 AND ($9::int = 0 OR version = $9) RETURNING version;`
 
-func (repo *AttributesRepository) Update(attributes domain.AttributesRequest, id int) error {
-	_, err := repo.DB.Exec(UpdateAttributesQuery, attributes.IDPosition, attributes.PAC, attributes.SHO, attributes.PAS, attributes.DRI, attributes.DEF, attributes.PHY, id)
+const GetAttributesVersionQuery = `SELECT version FROM attributes WHERE id = $1;`
+
+// Update replaces the stat weights of a position, guarded by version unless
+// it is 0. Moving them to a position that already has weights is a conflict.
+func (repo *AttributesRepository) Update(ctx context.Context, attributes domain.AttributesRequest, id int, version int) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	var newVersion int
+	err := repo.DB.QueryRowContext(ctx, UpdateAttributesQuery, attributes.IDPosition, attributes.PAC, attributes.SHO, attributes.PAS, attributes.DRI, attributes.DEF, attributes.PHY, id, version).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, staleOrNotFound(ctx, repo.DB, GetAttributesVersionQuery, id, version, "attributes %d", id)
+	}
 	if err != nil {
-		return err
+		return 0, conflictOnUniqueViolation(invalidOnDataError(err), "attributes for position %d already exist", attributes.IDPosition)
 	}
-	return nil
+	return newVersion, nil
 }
 
 const DeleteAttributesQuery = `DELETE FROM attributes WHERE id = $1;`
 
-func (repo *AttributesRepository) Delete(id int) error {
-	_, err := repo.DB.Exec(DeleteAttributesQuery, id)
+func (repo *AttributesRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	result, err := repo.DB.ExecContext(ctx, DeleteAttributesQuery, id)
 	if err != nil {
 		return err
 	}
-	return nil
+	return notFoundOnNoneAffected(result, "attributes %d not found", id)
 }
@@ -1,106 +1,34 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"rachao/internal/core/domain"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type CardPlayRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
 }
 
-const GetCardPlayAllQuery = `SELECT * FROM play p INNER JOIN card c ON p.id = c.id_play WHERE p.active = true;`
+// cardPlayColumns is the projection of the play/card join scanned by
+// scanCardPlay, in the same order, NULLs read as in playColumns.
+const cardPlayColumns = `p.id, p.name, coalesce(p.id_position, 0), coalesce(p.id_nation, 0), p.field, p.active, p.version, ` +
+	`c.id, c.id_play, c.pac, c.sho, c.pas, c.dri, c.def, c.phy, c.version`
 
-func (repo *CardPlayRepository) GetAll() ([]domain.CardPlay, error) {
-	rows, err := repo.DB.Query(GetCardPlayAllQuery)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var cardPlays []domain.CardPlay
-	for rows.Next() {
-		var cardPlay domain.CardPlay
-		if err := rows.Scan(
-			&cardPlay.Play.ID,
-			&cardPlay.Play.Name,
-			&cardPlay.Play.IDPosition,
-			&cardPlay.Play.IDNation,
-			&cardPlay.Play.Field,
-			&cardPlay.Play.Active,
-			&cardPlay.Card.ID,
-			&cardPlay.Card.IDPlay,
-			&cardPlay.Card.PAC,
-			&cardPlay.Card.SHO,
-			&cardPlay.Card.PAS,
-			&cardPlay.Card.DRI,
-			&cardPlay.Card.DEF,
-			&cardPlay.Card.PHY,
-		); err != nil {
-			return nil, err
-		}
-		cardPlays = append(cardPlays, cardPlay)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return cardPlays, nil
-}
-
-const GetCardPlayAllByInactiveQuery = `SELECT * FROM play p INNER JOIN card c ON p.id = c.id_play WHERE p.active = false;`
-
-func (repo *CardPlayRepository) GetAllByInactive() ([]domain.CardPlay, error) {
-	rows, err := repo.DB.Query(GetCardPlayAllByInactiveQuery)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var cardPlays []domain.CardPlay
-	for rows.Next() {
-		var cardPlay domain.CardPlay
-		if err := rows.Scan(
-			&cardPlay.Play.ID,
-			&cardPlay.Play.Name,
-			&cardPlay.Play.IDPosition,
-			&cardPlay.Play.IDNation,
-			&cardPlay.Play.Field,
-			&cardPlay.Play.Active,
-			&cardPlay.Card.ID,
-			&cardPlay.Card.IDPlay,
-			&cardPlay.Card.PAC,
-			&cardPlay.Card.SHO,
-			&cardPlay.Card.PAS,
-			&cardPlay.Card.DRI,
-			&cardPlay.Card.DEF,
-			&cardPlay.Card.PHY,
-		); err != nil {
-			return nil, err
-		}
-		cardPlays = append(cardPlays, cardPlay)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return cardPlays, nil
-}
-
-const GetCardPlayByIDQuery = `SELECT * FROM play p INNER JOIN card c ON p.id = c.id_play WHERE p.id = $1;`
-
-func (repo *CardPlayRepository) GetByID(id uuid.UUID) (domain.CardPlay, error) {
-	row := repo.DB.QueryRow(GetCardPlayByIDQuery, id)
+func scanCardPlay(row rowScanner) (domain.CardPlay, error) {
 	var cardPlay domain.CardPlay
-	if err := row.Scan(
+	err := row.Scan(
 		&cardPlay.Play.ID,
 		&cardPlay.Play.Name,
 		&cardPlay.Play.IDPosition,
 		&cardPlay.Play.IDNation,
 		&cardPlay.Play.Field,
 		&cardPlay.Play.Active,
+		&cardPlay.Play.Version,
 		&cardPlay.Card.ID,
 		&cardPlay.Card.IDPlay,
 		&cardPlay.Card.PAC,
@@ -109,11 +37,59 @@ func (repo *CardPlayRepository) GetByID(id uuid.UUID) (domain.CardPlay, error) {
 		&cardPlay.Card.DRI,
 		&cardPlay.Card.DEF,
 		&cardPlay.Card.PHY,
-	); err != nil {
-		if err == sql.ErrNoRows {
-			return cardPlay, nil
-		}
-		return cardPlay, err
+		&cardPlay.Card.Version,
+	)
+	return cardPlay, err
+}
+
+const GetCardPlayAllQuery = `SELECT ` + cardPlayColumns + ` FROM play p INNER JOIN card c ON p.id = c.id_play WHERE p.active = true;`
+
+func (repo *CardPlayRepository) GetAll(ctx context.Context) ([]domain.CardPlay, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetCardPlayAllQuery)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanCardPlay)
+}
+
+const GetCardPlayAllByInactiveQuery = `SELECT ` + cardPlayColumns + ` FROM play p INNER JOIN card c ON p.id = c.id_play WHERE p.active = false;`
+
+func (repo *CardPlayRepository) GetAllByInactive(ctx context.Context) ([]domain.CardPlay, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetCardPlayAllByInactiveQuery)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanCardPlay)
+}
+
+const GetCardPlayByIDQuery = `SELECT ` + cardPlayColumns + ` FROM play p INNER JOIN card c ON p.id = c.id_play WHERE p.id = $1;`
+
+func (repo *CardPlayRepository) GetByID(ctx context.Context, id uuid.UUID) (domain.CardPlay, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	cardPlay, err := scanCardPlay(repo.DB.QueryRowContext(ctx, GetCardPlayByIDQuery, id))
+	if err != nil {
+		return cardPlay, notFoundOnNoRows(err, "card play %s not found", id)
 	}
 	return cardPlay, nil
 }
+
+const GetCardPlayByIDPositionQuery = `SELECT ` + cardPlayColumns + ` FROM play p INNER JOIN card c ON p.id = c.id_play WHERE p.id_position = $1 AND p.active = true;`
+
+func (repo *CardPlayRepository) GetByIDPosition(ctx context.Context, idPosition int) ([]domain.CardPlay, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetCardPlayByIDPositionQuery, idPosition)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanCardPlay)
+}
@@ -1,76 +1,100 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"rachao/internal/core/domain"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type CardRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
 }
 
-const GetByIDQuery = `SELECT * FROM card WHERE id_play = $1;`
+// cardColumns is the projection scanned by scanCard, in the same order.
+const cardColumns = `id, id_play, pac, sho, pas, dri, def, phy, version`
 
-func (repo *CardRepository) GetByIDPlay(id uuid.UUID) (domain.Card, error) {
-	row := repo.DB.QueryRow(GetByIDQuery, id)
+func scanCard(row rowScanner) (domain.Card, error) {
 	var card domain.Card
-	if err := row.Scan(&card.ID, &card.IDPlay, &card.PAC, &card.SHO, &card.PAS, &card.DRI, &card.DEF, &card.PHY); err != nil {
-		return domain.Card{}, err
-	}
-	return card, nil
+	err := row.Scan(&card.ID, &card.IDPlay, &card.PAC, &card.SHO, &card.PAS, &card.DRI, &card.DEF, &card.PHY, &card.Version)
+	return card, err
 }
 
-const GetByID = `SELECT * FROM card WHERE id = $1;`
+const GetByIDQuery = `SELECT ` + cardColumns + ` FROM card WHERE id_play = $1;`
 
-func (repo *CardRepository) GetByID(id uuid.UUID) (domain.Card, error) {
-	row := repo.DB.QueryRow(GetByID, id)
-	var card domain.Card
-	if err := row.Scan(&card.ID, &card.IDPlay, &card.PAC, &card.SHO, &card.PAS, &card.DRI, &card.DEF, &card.PHY); err != nil {
-		return domain.Card{}, err
+func (repo *CardRepository) GetByIDPlay(ctx context.Context, id uuid.UUID) (domain.Card, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	card, err := scanCard(repo.DB.QueryRowContext(ctx, GetByIDQuery, id))
+	if err != nil {
+		return domain.Card{}, notFoundOnNoRows(err, "card for play %s not found", id)
 	}
 	return card, nil
 }
 
-const CreateQuery = `INSERT INTO card (id_play, pac, sho, pas, dri, def, phy) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`
+const GetByID = `SELECT ` + cardColumns + ` FROM card WHERE id = $1;`
 
-func (repo *CardRepository) Create(ID uuid.UUID, card domain.CardRequest) (uuid.UUID, error) {
-	var id uuid.UUID
+func (repo *CardRepository) GetByID(ctx context.Context, id uuid.UUID) (domain.Card, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
 
-	var idPlayExists bool
-	err := repo.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM card WHERE id_play = $1)", ID).Scan(&idPlayExists)
+	card, err := scanCard(repo.DB.QueryRowContext(ctx, GetByID, id))
 	if err != nil {
-		return uuid.Nil, err
-	}
-	if idPlayExists {
-		return uuid.Nil, sql.ErrNoRows
+		return domain.Card{}, notFoundOnNoRows(err, "card %s not found", id)
 	}
+	return card, nil
+}
+
+const CreateQuery = `INSERT INTO card (id_play, pac, sho, pas, dri, def, phy) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`
 
-	err = repo.DB.QueryRow(CreateQuery, ID, card.PAC, card.SHO, card.PAS, card.DRI, card.DEF, card.PHY).Scan(&id)
+func (repo *CardRepository) Create(ctx context.Context, ID uuid.UUID, card domain.CardRequest) (uuid.UUID, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	var id uuid.UUID
+	err := repo.DB.QueryRowContext(ctx, CreateQuery, ID, card.PAC, card.SHO, card.PAS, card.DRI, card.DEF, card.PHY).Scan(&id)
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, conflictOnUniqueViolation(invalidOnDataError(err), "card for play %s already exists", ID)
 	}
 	return id, nil
 }
 
-const UpdateQuery = `UPDATE card SET pac = $1, sho = $2, pas = $3, dri = $4, def = $5, phy = $6 WHERE id_play = $7;`
+const UpdateQuery = `UPDATE card SET pac = $1, sho = $2, pas = $3, dri = $4, def = $5, phy = $6, version = version + 1
+  WHERE id_play = $7 AND ($8::int = 0 OR version = $8) RETURNING id, version;`
 
-func (repo *CardRepository) Update(id uuid.UUID, card domain.CardRequest) (idCard uuid.UUID, erro error) {
-	result, err := repo.DB.Exec(UpdateQuery, card.PAC, card.SHO, card.PAS, card.DRI, card.DEF, card.PHY, id)
-	if err != nil {
-		return uuid.Nil, err
-	}
-	cardResult, err := repo.GetByIDPlay(id)
-	if err != nil {
-		return uuid.Nil, err
+const GetCardVersionQuery = `SELECT version FROM card WHERE id_play = $1;`
+
+// Update replaces the six stats on the card of play id, guarded by version
+// unless it is 0. The card ID is returned so the caller can request its
+// overall.
+func (repo *CardRepository) Update(ctx context.Context, id uuid.UUID, card domain.CardRequest, version int) (idCard uuid.UUID, newVersion int, erro error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	err := repo.DB.QueryRowContext(ctx, UpdateQuery, card.PAC, card.SHO, card.PAS, card.DRI, card.DEF, card.PHY, id, version).Scan(&idCard, &newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, 0, staleOrNotFound(ctx, repo.DB, GetCardVersionQuery, id, version, "card for play %s", id)
 	}
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return uuid.Nil, err
-	}
-	if rowsAffected == 0 {
-		return uuid.Nil, sql.ErrNoRows
+		return uuid.Nil, 0, invalidOnDataError(err)
 	}
-	return cardResult.ID, nil
+	return idCard, newVersion, nil
+}
+
+const CountCardWithoutOverallQuery = `SELECT count(*) FROM card c
+  LEFT JOIN overall o ON o.id_play = c.id_play
+  WHERE o.id_play IS NULL;`
+
+func (repo *CardRepository) CountWithoutOverall(ctx context.Context) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	var count int
+	err := repo.DB.QueryRowContext(ctx, CountCardWithoutOverallQuery).Scan(&count)
+	return count, err
 }
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"rachao/internal/core/domain"
+
+	"github.com/lib/pq"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+	checkViolation      = "23514"
+	stringTooLong       = "22001"
+)
+
+// notFoundOnNoRows turns the sql.ErrNoRows of a single-row lookup into a
+// domain NotFound error and passes any other error through.
+func notFoundOnNoRows(err error, format string, args ...any) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NewNotFoundError(format, args...)
+	}
+	return err
+}
+
+// notFoundOnNoneAffected reports a domain NotFound error when an UPDATE or
+// DELETE did not match any row.
+func notFoundOnNoneAffected(result sql.Result, format string, args ...any) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.NewNotFoundError(format, args...)
+	}
+	return nil
+}
+
+// conflictOnUniqueViolation turns a unique constraint violation into a
+// domain Conflict error, so concurrent writers racing for the same key get
+// the same answer as a sequential duplicate.
+func conflictOnUniqueViolation(err error, format string, args ...any) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return domain.NewConflictError(format, args...)
+	}
+	return err
+}
+
+// invalidOnDataError turns the errors Postgres raises for bad input (a value
+// longer than its column, a missing referenced row, a failed CHECK) into a
+// domain validation error instead of letting them surface as a 500. Request
+// validation should catch these first; this is the backstop.
+func invalidOnDataError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	field := pqErr.Column
+	if field == "" {
+		field = "body"
+	}
+	switch pqErr.Code {
+	case stringTooLong:
+		return domain.NewFieldError(field, "value is too long")
+	case foreignKeyViolation:
+		return domain.NewFieldError(field, "references a record that does not exist (%s)", pqErr.Constraint)
+	case checkViolation:
+		return domain.NewFieldError(field, "violates %s", pqErr.Constraint)
+	}
+	return err
+}
+
+// staleOrNotFound explains why a versioned UPDATE matched no row: either the
+// row is gone, or its version moved on since the caller read it. what names
+// the record, e.g. "play %s".
+func staleOrNotFound(ctx context.Context, db *sql.DB, versionQuery string, id any, expected int, what string, args ...any) error {
+	var current int
+	if err := db.QueryRowContext(ctx, versionQuery, id).Scan(&current); err != nil {
+		return notFoundOnNoRows(err, what+" not found", args...)
+	}
+	return domain.NewPreconditionFailedError(what+" is at version %d, not %d", append(args, current, expected)...)
+}
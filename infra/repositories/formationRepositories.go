@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"rachao/internal/core/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type FormationRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+// formationColumns is the projection scanned by scanFormation, in the same
+// order. The lines come as parallel arrays ordered back to front.
+const formationColumns = `f.id, f.id_modality, f.name,
+    array(SELECT id_position FROM formation_line WHERE id_formation = f.id ORDER BY line),
+    array(SELECT slots FROM formation_line WHERE id_formation = f.id ORDER BY line)`
+
+func scanFormation(row rowScanner) (domain.Formation, error) {
+	var formation domain.Formation
+	var positions, slots pq.Int64Array
+	if err := row.Scan(&formation.ID, &formation.IDModality, &formation.Name, &positions, &slots); err != nil {
+		return formation, err
+	}
+	for i := range positions {
+		formation.Lines = append(formation.Lines, domain.FormationLine{IDPosition: int(positions[i]), Slots: int(slots[i])})
+	}
+	return formation, nil
+}
+
+const GetFormationByModalityQuery = `SELECT ` + formationColumns + ` FROM formation f WHERE f.id_modality = $1 ORDER BY f.name;`
+
+func (repo *FormationRepository) GetByModality(ctx context.Context, idModality int) ([]domain.Formation, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetFormationByModalityQuery, idModality)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanFormation)
+}
+
+const GetFormationByIDQuery = `SELECT ` + formationColumns + ` FROM formation f WHERE f.id = $1;`
+
+func (repo *FormationRepository) GetByID(ctx context.Context, id int) (domain.Formation, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	formation, err := scanFormation(repo.DB.QueryRowContext(ctx, GetFormationByIDQuery, id))
+	if err != nil {
+		return formation, notFoundOnNoRows(err, "formation %d not found", id)
+	}
+	return formation, nil
+}
+
+const CreateFormationQuery = `INSERT INTO formation (id_modality, name) VALUES ($1, $2) RETURNING id;`
+
+const CreateFormationLinesQuery = `INSERT INTO formation_line (id_formation, line, id_position, slots)
+  SELECT $1, t.line - 1, t.id_position, t.slots
+  FROM unnest($2::int[], $3::int[]) WITH ORDINALITY AS t(id_position, slots, line);`
+
+// Create stores the formation and its lines in one transaction.
+func (repo *FormationRepository) Create(ctx context.Context, formation domain.CreateFormationRequest) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, CreateFormationQuery, formation.IDModality, formation.Name).Scan(&id)
+	if err != nil {
+		err = conflictOnUniqueViolation(err, "formation %q already exists for modality %d", formation.Name, formation.IDModality)
+		return 0, invalidOnDataError(err)
+	}
+
+	positions := make([]int64, len(formation.Lines))
+	slots := make([]int64, len(formation.Lines))
+	for i, line := range formation.Lines {
+		positions[i], slots[i] = int64(line.IDPosition), int64(line.Slots)
+	}
+	if _, err := tx.ExecContext(ctx, CreateFormationLinesQuery, id, pq.Array(positions), pq.Array(slots)); err != nil {
+		return 0, invalidOnDataError(err)
+	}
+	return id, tx.Commit()
+}
+
+const DeleteFormationQuery = `DELETE FROM formation WHERE id = $1;`
+
+func (repo *FormationRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	result, err := repo.DB.ExecContext(ctx, DeleteFormationQuery, id)
+	if err != nil {
+		return err
+	}
+	return notFoundOnNoneAffected(result, "formation %d not found", id)
+}
+
+const GetLineupPlayersQuery = `SELECT p.id, p.name, coalesce(p.id_position, 0), o.overall
+  FROM play p
+  LEFT JOIN overall o ON o.id_play = p.id
+  WHERE p.id = ANY($1::uuid[]) AND p.active = true;`
+
+// GetPlayers returns the active players among ids with their position and
+// overall. Unknown and inactive ones are left out.
+func (repo *FormationRepository) GetPlayers(ctx context.Context, ids []uuid.UUID) ([]domain.LineupPlayer, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	players := make([]string, len(ids))
+	for i, id := range ids {
+		players[i] = id.String()
+	}
+	rows, err := repo.DB.QueryContext(ctx, GetLineupPlayersQuery, pq.Array(players))
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, func(row rowScanner) (domain.LineupPlayer, error) {
+		var player domain.LineupPlayer
+		err := row.Scan(&player.IDPlay, &player.Name, &player.IDPosition, &player.Overall)
+		return player, err
+	})
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"testing"
+
+	"rachao/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestFormationProjections(t *testing.T) {
+	f := newFixture(t)
+	positions := PositionRepository{DB: f.db, Timeout: testTimeout}
+	back := must(positions.Create(f.ctx, domain.CreatePositionRequest{Name: unique("Zagueiro"), Acronym: "ZAG"}))(t)
+
+	formations := FormationRepository{DB: f.db, Timeout: testTimeout}
+	id := must(formations.Create(f.ctx, domain.CreateFormationRequest{
+		IDModality: f.modality,
+		Name:       "1-1",
+		Lines:      []domain.FormationLine{{IDPosition: back, Slots: 1}, {IDPosition: f.position, Slots: 1}},
+	}))(t)
+	formation := must(formations.GetByID(f.ctx, id))(t)
+	if formation.IDModality != f.modality || len(formation.Lines) != 2 || formation.Lines[0].IDPosition != back || formation.Lines[1].Slots != 1 {
+		t.Errorf("formation = %+v", formation)
+	}
+	if byModality := must(formations.GetByModality(f.ctx, f.modality))(t); len(byModality) != 1 || byModality[0].ID != id {
+		t.Errorf("modality formations = %+v", byModality)
+	}
+
+	player := f.play(t, &testCard, 70)
+	if players := must(formations.GetPlayers(f.ctx, []uuid.UUID{player, uuid.New()}))(t); len(players) != 1 || *players[0].Overall != 70 || players[0].IDPosition != f.position {
+		t.Errorf("lineup players = %+v", players)
+	}
+}
+
+func TestFormationPlayersReadNulls(t *testing.T) {
+	f := newFixture(t)
+	id := uuid.New()
+	mustExec(t, f.db, `INSERT INTO play (id, name, id_position, id_nation) VALUES ($1, $2, NULL, NULL)`, id, unique("Legado"))
+
+	formations := FormationRepository{DB: f.db, Timeout: testTimeout}
+	if players := must(formations.GetPlayers(f.ctx, []uuid.UUID{id}))(t); len(players) != 1 || players[0].IDPosition != 0 || players[0].Overall != nil {
+		t.Errorf("lineup players = %+v", players)
+	}
+}
@@ -1,80 +1,130 @@
 package repositories
 
 import (
+	"context"
 	"rachao/internal/core/domain"
 
 	"github.com/google/uuid"
 )
 
 type PlayRepositoryInterface interface {
-	GetAll() ([]domain.Play, error)
-	GetAllByInactive() ([]domain.Play, error)
-	GetByID(id uuid.UUID) (domain.Play, error)
-	Create(play domain.CreatePlayRequest) (uuid.UUID, error)
-	Update(id uuid.UUID, play domain.Play) error
-	Delete(id uuid.UUID) error
-	GetByName(name string) (domain.Play, error)
+	GetAll(ctx context.Context) ([]domain.Play, error)
+	GetAllByInactive(ctx context.Context) ([]domain.Play, error)
+	GetByID(ctx context.Context, id uuid.UUID) (domain.Play, error)
+	Create(ctx context.Context, play domain.CreatePlayRequest) (uuid.UUID, error)
+	Update(ctx context.Context, id uuid.UUID, play domain.Play, version int) (int, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Activate(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, id uuid.UUID) error
+	GetByName(ctx context.Context, name string) (domain.Play, error)
+	CountActive(ctx context.Context) (int, error)
 }
 
 type CardRepositoryInterface interface {
-	GetByIDPlay(id uuid.UUID) (domain.Card, error)
-	GetByID(id uuid.UUID) (domain.Card, error)
-	Create(id uuid.UUID, card domain.CardRequest) (uuid.UUID, error)
-	Update(id uuid.UUID, card domain.CardRequest) (idCard uuid.UUID, erro error)
+	GetByIDPlay(ctx context.Context, id uuid.UUID) (domain.Card, error)
+	GetByID(ctx context.Context, id uuid.UUID) (domain.Card, error)
+	Create(ctx context.Context, id uuid.UUID, card domain.CardRequest) (uuid.UUID, error)
+	Update(ctx context.Context, id uuid.UUID, card domain.CardRequest, version int) (idCard uuid.UUID, newVersion int, erro error)
+	CountWithoutOverall(ctx context.Context) (int, error)
 }
 
 type CardPlayRepositoryInterface interface {
-	GetAll() ([]domain.CardPlay, error)
-	GetAllByInactive() ([]domain.CardPlay, error)
-	GetByID(id uuid.UUID) (domain.CardPlay, error)
+	GetAll(ctx context.Context) ([]domain.CardPlay, error)
+	GetAllByInactive(ctx context.Context) ([]domain.CardPlay, error)
+	GetByID(ctx context.Context, id uuid.UUID) (domain.CardPlay, error)
+	GetByIDPosition(ctx context.Context, idPosition int) ([]domain.CardPlay, error)
 }
 
 type NationRepositoryInterface interface {
-	GetAll() ([]domain.Nation, error)
-	GetByID(id int) (domain.Nation, error)
-	Create(nation domain.CreateNationRequest) (int, error)
-	Update(id int, nation domain.Nation) error
+	GetAll(ctx context.Context) ([]domain.Nation, error)
+	GetByID(ctx context.Context, id int) (domain.Nation, error)
+	Create(ctx context.Context, nation domain.CreateNationRequest) (int, error)
+	Update(ctx context.Context, id int, nation domain.Nation, version int) (int, error)
 }
 
 type PhotoRepositoryInterface interface {
-	GetByIDPlay(idPlay uuid.UUID) ([]domain.Photo, error)
-	Create(idPlay uuid.UUID, photo []byte) (uuid.UUID, error)
-	Update(idPlay uuid.UUID, photo []byte) error
-	Delete(idPlay uuid.UUID) error
+	GetByIDPlay(ctx context.Context, idPlay uuid.UUID) ([]domain.Photo, error)
+	Create(ctx context.Context, idPlay uuid.UUID, photo []byte) (uuid.UUID, error)
+	Update(ctx context.Context, idPlay uuid.UUID, photo []byte) error
+	Delete(ctx context.Context, idPlay uuid.UUID) error
 }
 
 type PositionRepositoryInterface interface {
-	GetAll() ([]domain.Position, error)
-	GetByID(id int) (domain.Position, error)
-	Create(position domain.CreatePositionRequest) (int, error)
-	Update(id int, position domain.Position) error
-	Delete(id int) error
+	GetAll(ctx context.Context) ([]domain.Position, error)
+	GetByID(ctx context.Context, id int) (domain.Position, error)
+	Create(ctx context.Context, position domain.CreatePositionRequest) (int, error)
+	Update(ctx context.Context, id int, position domain.Position, version int) (int, error)
+	Delete(ctx context.Context, id int) error
 }
 
 type AttributeRepositoryInterface interface {
-	GetByIDPosition(idPosition int) (domain.Attributes, error)
-	GetByIDAttributes(id int) (domain.Attributes, error)
-	GetAll() ([]domain.Attributes, error)
-	Create(attributes domain.AttributesRequest) (int, error)
-	Update(attributes domain.AttributesRequest, id int) error
-	Delete(id int) error
+	GetByIDPosition(ctx context.Context, idPosition int) (domain.Attributes, error)
+	GetByIDAttributes(ctx context.Context, id int) (domain.Attributes, error)
+	GetAll(ctx context.Context) ([]domain.Attributes, error)
+	Create(ctx context.Context, attributes domain.AttributesRequest) (int, error)
+	Update(ctx context.Context, attributes domain.AttributesRequest, id int, version int) (int, error)
+	Delete(ctx context.Context, id int) error
 }
 
 type OverallRepositoryInterface interface {
-	Exists(idPlay uuid.UUID) (bool, error)
-	GetByIDPlay(idUser uuid.UUID) (domain.Overall, error)
-	Create(overall domain.OverallRequest) (uuid.UUID, error)
-	Update(overall domain.OverallRequest, idUser uuid.UUID) error
-	Delete(idUser uuid.UUID) error
+	Exists(ctx context.Context, idPlay uuid.UUID) (bool, error)
+	GetByIDPlay(ctx context.Context, idUser uuid.UUID) (domain.Overall, error)
+	Create(ctx context.Context, overall domain.OverallRequest) (uuid.UUID, error)
+	Upsert(ctx context.Context, overall domain.OverallRequest) (uuid.UUID, error)
+	Update(ctx context.Context, overall domain.OverallRequest, idUser uuid.UUID) error
+	Delete(ctx context.Context, idUser uuid.UUID) error
 }
 
 type ModalityRepositoryInterface interface {
-	GetAll() ([]domain.Modality, error)
-	GetAllByInactive() ([]domain.Modality, error)
-	GetByID(id int) (domain.Modality, error)
-	Create(modality domain.CreateModalityRequest) (int, error)
-	Update(modality domain.Modality) error
-	Inactive(id int) error
-	Active(id int) error
-	GetByName(name string) (domain.Modality, error)
+	GetAll(ctx context.Context) ([]domain.Modality, error)
+	GetAllByInactive(ctx context.Context) ([]domain.Modality, error)
+	GetByID(ctx context.Context, id int) (domain.Modality, error)
+	Create(ctx context.Context, modality domain.CreateModalityRequest) (int, error)
+	Update(ctx context.Context, modality domain.Modality, version int) (int, error)
+	Inactive(ctx context.Context, id int) error
+	Active(ctx context.Context, id int) error
+	GetByName(ctx context.Context, name string) (domain.Modality, error)
+}
+
+type SeasonRepositoryInterface interface {
+	GetAll(ctx context.Context) ([]domain.Season, error)
+	GetByID(ctx context.Context, id int) (domain.Season, error)
+	Create(ctx context.Context, season domain.CreateSeasonRequest) (int, error)
+	Update(ctx context.Context, id int, season domain.Season, version int) (int, error)
+	Close(ctx context.Context, id int) (int, error)
+	GetSnapshots(ctx context.Context, id int) ([]domain.SeasonSnapshot, error)
+	GetStats(ctx context.Context, id int) ([]domain.SeasonPlayerStats, error)
+}
+
+type MatchRepositoryInterface interface {
+	GetByID(ctx context.Context, id uuid.UUID) (domain.Match, error)
+	GetBySeason(ctx context.Context, idSeason int) ([]domain.Match, error)
+	Create(ctx context.Context, match domain.CreateMatchRequest) (uuid.UUID, error)
+	RecordResult(ctx context.Context, id uuid.UUID, result domain.MatchResultRequest, version int) (int, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type StatsRepositoryInterface interface {
+	Leaderboard(ctx context.Context, query domain.LeaderboardQuery) ([]domain.LeaderboardEntry, error)
+	PlayerStats(ctx context.Context, id uuid.UUID, query domain.PlayerStatsQuery) (domain.PlayerStats, error)
+}
+
+type RatingRepositoryInterface interface {
+	GetAll(ctx context.Context) ([]domain.Rating, error)
+	GetHistory(ctx context.Context, idPlay uuid.UUID) ([]domain.RatingChange, error)
+	Rebuild(ctx context.Context, compute func([]domain.Match) []domain.RatingChange) (int, error)
+	Append(ctx context.Context, match domain.Match, rate func(map[uuid.UUID]float64, domain.Match) []domain.RatingChange) (bool, error)
+}
+
+type PredictionRepositoryInterface interface {
+	GetMatch(ctx context.Context, id uuid.UUID) (domain.PredictionMatch, error)
+	GetHistory(ctx context.Context, id uuid.UUID) ([]domain.PredictionMatch, error)
+}
+
+type FormationRepositoryInterface interface {
+	GetByModality(ctx context.Context, idModality int) ([]domain.Formation, error)
+	GetByID(ctx context.Context, id int) (domain.Formation, error)
+	Create(ctx context.Context, formation domain.CreateFormationRequest) (int, error)
+	Delete(ctx context.Context, id int) error
+	GetPlayers(ctx context.Context, ids []uuid.UUID) ([]domain.LineupPlayer, error)
 }
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"rachao/db/migrations"
+	"rachao/infra/migrate"
+
+	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// testDSNEnv names the database the integration tests run against. They are
+// skipped when it is unset. Each run migrates a schema of its own and drops
+// it afterwards.
+const testDSNEnv = "TEST_DATABASE_URL"
+
+const testTimeout = 5 * time.Second
+
+// testDB is the migrated database, nil when the tests are skipped.
+var testDB *sql.DB
+
+func TestMain(m *testing.M) {
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		os.Exit(m.Run())
+	}
+	schema := fmt.Sprintf("rachao_test_%d", os.Getpid())
+	db, err := openTestSchema(dsn, schema)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "repositories tests:", err)
+		os.Exit(1)
+	}
+	testDB = db
+	code := m.Run()
+
+	db.Close()
+	if admin, err := sql.Open("postgres", dsn); err == nil {
+		admin.Exec(`DROP SCHEMA "` + schema + `" CASCADE`)
+		admin.Close()
+	}
+	os.Exit(code)
+}
+
+// openTestSchema creates schema, connects with it as the search path and
+// applies every migration in db/migrations.
+func openTestSchema(dsn, schema string) (*sql.DB, error) {
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer admin.Close()
+	if _, err := admin.Exec(`CREATE SCHEMA "` + schema + `"`); err != nil {
+		return nil, err
+	}
+
+	if strings.Contains(dsn, "://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return nil, err
+		}
+		query := u.Query()
+		query.Set("search_path", schema)
+		u.RawQuery = query.Encode()
+		dsn = u.String()
+	} else {
+		dsn += " search_path=" + schema
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := migrate.NewMigrator(db, migrations.FS, zap.NewNop()).Up(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// requireDB returns the test database or skips the test.
+func requireDB(t *testing.T) *sql.DB {
+	t.Helper()
+	if testDB == nil {
+		t.Skip(testDSNEnv + " is not set")
+	}
+	return testDB
+}
+
+// mustExec runs a fixture statement that bypasses the repositories, e.g. to
+// write the NULLs the API never writes.
+func mustExec(t *testing.T, db *sql.DB, query string, args ...any) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+}
+
+// must unwraps the result of a repository call, failing the test on error:
+// must(repo.GetByID(ctx, id))(t).
+func must[T any](value T, err error) func(*testing.T) T {
+	return func(t *testing.T) T {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"rachao/internal/core/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type MatchRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+// matchColumns is the projection scanned by scanMatch, in the same order.
+const matchColumns = `id, id_season, id_modality, played_at, home_goals, away_goals, version`
+
+func scanMatch(row rowScanner) (domain.Match, error) {
+	var match domain.Match
+	err := row.Scan(&match.ID, &match.IDSeason, &match.IDModality, &match.PlayedAt, &match.HomeGoals, &match.AwayGoals, &match.Version)
+	return match, err
+}
+
+const GetMatchByIDQuery = `SELECT ` + matchColumns + ` FROM match WHERE id = $1;`
+
+const GetMatchPlayersQuery = `SELECT mp.id_play, p.name, mp.team, mp.overall, mp.goals, mp.assists
+  FROM match_player mp
+  JOIN play p ON p.id = mp.id_play
+  WHERE mp.id_match = $1
+  ORDER BY mp.team DESC, p.name;`
+
+// GetByID returns the match with its players.
+func (repo *MatchRepository) GetByID(ctx context.Context, id uuid.UUID) (domain.Match, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	match, err := scanMatch(repo.DB.QueryRowContext(ctx, GetMatchByIDQuery, id))
+	if err != nil {
+		return match, notFoundOnNoRows(err, "match %s not found", id)
+	}
+	rows, err := repo.DB.QueryContext(ctx, GetMatchPlayersQuery, id)
+	if err != nil {
+		return match, err
+	}
+	match.Players, err = scanAll(rows, func(row rowScanner) (domain.MatchPlayer, error) {
+		var player domain.MatchPlayer
+		err := row.Scan(&player.IDPlay, &player.Name, &player.Team, &player.Overall, &player.Goals, &player.Assists)
+		return player, err
+	})
+	return match, err
+}
+
+const GetMatchBySeasonQuery = `SELECT ` + matchColumns + ` FROM match WHERE id_season = $1 ORDER BY played_at;`
+
+// GetBySeason lists the matches of a season without their players.
+func (repo *MatchRepository) GetBySeason(ctx context.Context, idSeason int) ([]domain.Match, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetMatchBySeasonQuery, idSeason)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanMatch)
+}
+
+const CreateMatchQuery = `INSERT INTO match (id_season, id_modality, played_at) VALUES ($1, $2, $3) RETURNING id;`
+
+// CreateMatchPlayersQuery registers both sides at once, recording each
+// player's current overall.
+const CreateMatchPlayersQuery = `INSERT INTO match_player (id_match, id_play, team, overall)
+  SELECT $1, t.id_play, t.team, o.overall
+  FROM unnest($2::uuid[], $3::text[]) AS t(id_play, team)
+  LEFT JOIN overall o ON o.id_play = t.id_play;`
+
+// Create registers a match and its players in one transaction.
+func (repo *MatchRepository) Create(ctx context.Context, match domain.CreateMatchRequest) (uuid.UUID, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	var id uuid.UUID
+	err = tx.QueryRowContext(ctx, CreateMatchQuery, match.IDSeason, match.IDModality, match.PlayedAt).Scan(&id)
+	if err != nil {
+		return uuid.Nil, invalidOnDataError(err)
+	}
+
+	var players, teams []string
+	for _, idPlay := range match.Home {
+		players, teams = append(players, idPlay.String()), append(teams, domain.TeamHome)
+	}
+	for _, idPlay := range match.Away {
+		players, teams = append(players, idPlay.String()), append(teams, domain.TeamAway)
+	}
+	if _, err := tx.ExecContext(ctx, CreateMatchPlayersQuery, id, pq.Array(players), pq.Array(teams)); err != nil {
+		return uuid.Nil, invalidOnDataError(err)
+	}
+	return id, tx.Commit()
+}
+
+const RecordMatchResultQuery = `UPDATE match SET home_goals = $1, away_goals = $2, version = version + 1
+  WHERE id = $3 AND ($4::int = 0 OR version = $4) RETURNING version;`
+
+const GetMatchVersionQuery = `SELECT version FROM match WHERE id = $1;`
+
+// RecordMatchPlayersQuery sets goals and assists for every player of the
+// match, zero for those missing from the arrays.
+const RecordMatchPlayersQuery = `UPDATE match_player mp
+  SET goals = coalesce(t.goals, 0), assists = coalesce(t.assists, 0)
+  FROM match_player m
+  LEFT JOIN unnest($2::uuid[], $3::int[], $4::int[]) AS t(id_play, goals, assists) ON t.id_play = m.id_play
+  WHERE mp.id_match = $1 AND m.id_match = mp.id_match AND m.id_play = mp.id_play;`
+
+// RecordResult stores the score and the players' goals and assists when the
+// match is still at version (0 skips the check) and returns the new version.
+func (repo *MatchRepository) RecordResult(ctx context.Context, id uuid.UUID, result domain.MatchResultRequest, version int) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var newVersion int
+	err = tx.QueryRowContext(ctx, RecordMatchResultQuery, result.HomeGoals, result.AwayGoals, id, version).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, staleOrNotFound(ctx, repo.DB, GetMatchVersionQuery, id, version, "match %s", id)
+	}
+	if err != nil {
+		return 0, invalidOnDataError(err)
+	}
+
+	players := make([]string, len(result.Players))
+	goals := make([]int64, len(result.Players))
+	assists := make([]int64, len(result.Players))
+	for i, player := range result.Players {
+		players[i] = player.IDPlay.String()
+		goals[i], assists[i] = int64(player.Goals), int64(player.Assists)
+	}
+	_, err = tx.ExecContext(ctx, RecordMatchPlayersQuery, id, pq.Array(players), pq.Array(goals), pq.Array(assists))
+	if err != nil {
+		return 0, invalidOnDataError(err)
+	}
+	return newVersion, tx.Commit()
+}
+
+const DeleteMatchQuery = `DELETE FROM match WHERE id = $1;`
+
+func (repo *MatchRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	result, err := repo.DB.ExecContext(ctx, DeleteMatchQuery, id)
+	if err != nil {
+		return err
+	}
+	return notFoundOnNoneAffected(result, "match %s not found", id)
+}
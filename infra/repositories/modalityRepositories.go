@@ -1,120 +1,138 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"rachao/internal/core/domain"
+	"time"
 )
 
 type ModalityRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
 }
 
-const GetModalityAllQuery = `SELECT * FROM modality WHERE active = true;`
+// modalityColumns is the projection scanned by scanModality, in the same
+// order. NULL columns, which the schema still allows, read as zero values.
+const modalityColumns = `id, coalesce(name, ''), coalesce(amount_play, 0), coalesce(active, false), version`
 
-func (repo *ModalityRepository) GetAll() ([]domain.Modality, error) {
-	rows, err := repo.DB.Query(GetModalityAllQuery)
+func scanModality(row rowScanner) (domain.Modality, error) {
+	var modality domain.Modality
+	err := row.Scan(&modality.ID, &modality.Name, &modality.Amount_play, &modality.Active, &modality.Version)
+	return modality, err
+}
+
+const GetModalityAllQuery = `SELECT ` + modalityColumns + ` FROM modality WHERE active = true;`
+
+func (repo *ModalityRepository) GetAll(ctx context.Context) ([]domain.Modality, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetModalityAllQuery)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var modalities []domain.Modality
-	for rows.Next() {
-		var modality domain.Modality
-		if err := rows.Scan(&modality.ID, &modality.Name, &modality.Amount_play, &modality.Active); err != nil {
-			return nil, err
-		}
-		modalities = append(modalities, modality)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return modalities, nil
+	return scanAll(rows, scanModality)
 }
 
-const GetModalityAllByInactiveQuery = `SELECT * FROM modality WHERE active = false;`
+const GetModalityAllByInactiveQuery = `SELECT ` + modalityColumns + ` FROM modality WHERE active = false;`
+
+func (repo *ModalityRepository) GetAllByInactive(ctx context.Context) ([]domain.Modality, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
 
-func (repo *ModalityRepository) GetAllByInactive() ([]domain.Modality, error) {
-	rows, err := repo.DB.Query(GetModalityAllByInactiveQuery)
+	rows, err := repo.DB.QueryContext(ctx, GetModalityAllByInactiveQuery)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var modalities []domain.Modality
-	for rows.Next() {
-		var modality domain.Modality
-		if err := rows.Scan(&modality.ID, &modality.Name, &modality.Amount_play, &modality.Active); err != nil {
-			return nil, err
-		}
-		modalities = append(modalities, modality)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return modalities, nil
+	return scanAll(rows, scanModality)
 }
 
-const GetModalityByIDQuery = `SELECT * FROM modality WHERE id = $1;`
+const GetModalityByIDQuery = `SELECT ` + modalityColumns + ` FROM modality WHERE id = $1;`
 
-func (repo *ModalityRepository) GetByID(id int) (domain.Modality, error) {
-	row := repo.DB.QueryRow(GetModalityByIDQuery, id)
-	var modality domain.Modality
-	if err := row.Scan(&modality.ID, &modality.Name, &modality.Amount_play, &modality.Active); err != nil {
-		return modality, err
+func (repo *ModalityRepository) GetByID(ctx context.Context, id int) (domain.Modality, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	modality, err := scanModality(repo.DB.QueryRowContext(ctx, GetModalityByIDQuery, id))
+	if err != nil {
+		return modality, notFoundOnNoRows(err, "modality %d not found", id)
 	}
 	return modality, nil
 }
 
 const CreateModalityQuery = `INSERT INTO modality (name, amount_play, active) VALUES ($1, $2, $3) RETURNING id;`
 
-func (repo *ModalityRepository) Create(modality domain.CreateModalityRequest) (int, error) {
+func (repo *ModalityRepository) Create(ctx context.Context, modality domain.CreateModalityRequest) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
 	var id int
-	err := repo.DB.QueryRow(CreateModalityQuery, modality.Name, modality.Amount_play, modality.Active).Scan(&id)
+	err := repo.DB.QueryRowContext(ctx, CreateModalityQuery, modality.Name, modality.Amount_play, modality.Active).Scan(&id)
 	if err != nil {
-		return 0, err
+		return 0, invalidOnDataError(err)
 	}
 	return id, nil
 }
 
-const UpdateModalityQuery = `UPDATE modality SET name = $1, amount_play = $2, active = $3 WHERE id = $4;`
+const UpdateModalityQuery = `UPDATE modality SET name = $1, amount_play = $2, active = $3, version = version + 1
+  WHERE id = $4 AND ($5::int = 0 OR version = $5) RETURNING version;`
 
-func (repo *ModalityRepository) Update(modality domain.Modality) error {
-	_, err := repo.DB.Exec(UpdateModalityQuery, modality.Name, modality.Amount_play, modality.Active, modality.ID)
+const GetModalityVersionQuery = `SELECT version FROM modality WHERE id = $1;`
+
+// Update saves the name, player count and active flag of modality.ID,
+// guarded by version unless it is 0, and returns the bumped version.
+func (repo *ModalityRepository) Update(ctx context.Context, modality domain.Modality, version int) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	var newVersion int
+	err := repo.DB.QueryRowContext(ctx, UpdateModalityQuery, modality.Name, modality.Amount_play, modality.Active, modality.ID, version).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, staleOrNotFound(ctx, repo.DB, GetModalityVersionQuery, modality.ID, version, "modality %d", modality.ID)
+	}
 	if err != nil {
-		return err
+		return 0, invalidOnDataError(err)
 	}
-	return nil
+	return newVersion, nil
 }
 
-const InactiveModalityQuery = `UPDATE modality SET active = false WHERE id = $1;`
+const InactiveModalityQuery = `UPDATE modality SET active = false, version = version + 1 WHERE id = $1;`
+
+func (repo *ModalityRepository) Inactive(ctx context.Context, id int) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
 
-func (repo *ModalityRepository) Inactive(id int) error {
-	_, err := repo.DB.Exec(InactiveModalityQuery, id)
+	result, err := repo.DB.ExecContext(ctx, InactiveModalityQuery, id)
 	if err != nil {
 		return err
 	}
-	return nil
+	return notFoundOnNoneAffected(result, "modality %d not found", id)
 }
 
-const ActiveModalityQuery = `UPDATE modality SET active = true WHERE id = $1;`
+const ActiveModalityQuery = `UPDATE modality SET active = true, version = version + 1 WHERE id = $1;`
+
+func (repo *ModalityRepository) Active(ctx context.Context, id int) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
 
-func (repo *ModalityRepository) Active(id int) error {
-	_, err := repo.DB.Exec(ActiveModalityQuery, id)
+	result, err := repo.DB.ExecContext(ctx, ActiveModalityQuery, id)
 	if err != nil {
 		return err
 	}
-	return nil
+	return notFoundOnNoneAffected(result, "modality %d not found", id)
 }
 
-const GetModalityByNameQuery = `SELECT * FROM modality WHERE name = $1;`
+const GetModalityByNameQuery = `SELECT ` + modalityColumns + ` FROM modality WHERE name = $1;`
 
-func (repo *ModalityRepository) GetByName(name string) (domain.Modality, error) {
-	row := repo.DB.QueryRow(GetModalityByNameQuery, name)
-	var modality domain.Modality
-	if err := row.Scan(&modality.ID, &modality.Name, &modality.Active); err != nil {
-		return modality, err
+func (repo *ModalityRepository) GetByName(ctx context.Context, name string) (domain.Modality, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	modality, err := scanModality(repo.DB.QueryRowContext(ctx, GetModalityByNameQuery, name))
+	if err != nil {
+		return modality, notFoundOnNoRows(err, "modality %q not found", name)
 	}
 	return modality, nil
 }
@@ -1,69 +1,86 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"rachao/internal/core/domain"
+	"time"
 )
 
 type NationRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
 }
 
-const GetNationAllQuery = `SELECT * FROM nation;`
+// nationColumns is the projection scanned by scanNation, in the same order.
+// The schema still allows NULL names and acronyms, which read as "".
+const nationColumns = `id, coalesce(name, ''), coalesce(acronym, ''), version`
 
-func (repo *NationRepository) GetAll() ([]domain.Nation, error) {
-	rows, err := repo.DB.Query(GetNationAllQuery)
+func scanNation(row rowScanner) (domain.Nation, error) {
+	var nation domain.Nation
+	err := row.Scan(&nation.ID, &nation.Name, &nation.Acronym, &nation.Version)
+	return nation, err
+}
+
+const GetNationAllQuery = `SELECT ` + nationColumns + ` FROM nation;`
+
+func (repo *NationRepository) GetAll(ctx context.Context) ([]domain.Nation, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetNationAllQuery)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var nations []domain.Nation
-	for rows.Next() {
-		var nation domain.Nation
-		if err := rows.Scan(&nation.ID, &nation.Name, &nation.Acronym); err != nil {
-			return nil, err
-		}
-		nations = append(nations, nation)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return nations, nil
+	return scanAll(rows, scanNation)
 }
 
-const GetNationByIDQuery = `SELECT * FROM nation WHERE id = $1;`
+const GetNationByIDQuery = `SELECT ` + nationColumns + ` FROM nation WHERE id = $1;`
 
-func (repo *NationRepository) GetByID(id int) (domain.Nation, error) {
-	row := repo.DB.QueryRow(GetNationByIDQuery, id)
-	var nation domain.Nation
-	if err := row.Scan(&nation.ID, &nation.Name, &nation.Acronym); err != nil {
-		if err == sql.ErrNoRows {
-			return domain.Nation{}, nil
-		}
-		return domain.Nation{}, err
+func (repo *NationRepository) GetByID(ctx context.Context, id int) (domain.Nation, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	nation, err := scanNation(repo.DB.QueryRowContext(ctx, GetNationByIDQuery, id))
+	if err != nil {
+		return domain.Nation{}, notFoundOnNoRows(err, "nation %d not found", id)
 	}
 	return nation, nil
 }
 
 const CreateNationQuery = `INSERT INTO nation (name, acronym) VALUES ($1, $2) RETURNING id;`
 
-func (repo *NationRepository) Create(nation domain.CreateNationRequest) (int, error) {
+func (repo *NationRepository) Create(ctx context.Context, nation domain.CreateNationRequest) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
 	var id int
-	err := repo.DB.QueryRow(CreateNationQuery, nation.Name, nation.Acronym).Scan(&id)
+	err := repo.DB.QueryRowContext(ctx, CreateNationQuery, nation.Name, nation.Acronym).Scan(&id)
 	if err != nil {
-		return 0, err
+		return 0, invalidOnDataError(err)
 	}
 	return id, nil
 }
 
-const UpdateNationQuery = `UPDATE nation SET name = $1, acronym = $2 WHERE id = $3;`
+const UpdateNationQuery = `UPDATE nation SET name = $1, acronym = $2, version = version + 1
+  WHERE id = $3 AND ($4::int = 0 OR version = $4) RETURNING version;`
+
+const GetNationVersionQuery = `SELECT version FROM nation WHERE id = $1;`
 
-func (repo *NationRepository) Update(id int, nation domain.Nation) error {
-	_, err := repo.DB.Exec(UpdateNationQuery, nation.Name, nation.Acronym, id)
+// Update renames a nation or changes its acronym, guarded by version unless
+// it is 0, and returns the bumped version.
+func (repo *NationRepository) Update(ctx context.Context, id int, nation domain.Nation, version int) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	var newVersion int
+	err := repo.DB.QueryRowContext(ctx, UpdateNationQuery, nation.Name, nation.Acronym, id, version).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, staleOrNotFound(ctx, repo.DB, GetNationVersionQuery, id, version, "nation %d", id)
+	}
 	if err != nil {
-		return err
+		return 0, invalidOnDataError(err)
 	}
-	return nil
+	return newVersion, nil
 }
@@ -1,77 +1,105 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"rachao/internal/core/domain"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type OverallRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
 }
 
 const ExistsOverallQuery = `SELECT EXISTS(SELECT 1 FROM overall WHERE id_play = $1);`
 
-func (repo *OverallRepository) Exists(idPlay uuid.UUID) (bool, error) {
+func (repo *OverallRepository) Exists(ctx context.Context, idPlay uuid.UUID) (bool, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
 	var exists bool
-	err := repo.DB.QueryRow(ExistsOverallQuery, idPlay).Scan(&exists)
+	err := repo.DB.QueryRowContext(ctx, ExistsOverallQuery, idPlay).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
 	return exists, nil
 }
 
-const GetByIDPlayQuery = `SELECT * FROM overall WHERE id_play = $1;`
+// overallColumns is the projection scanned by scanOverall, in the same order.
+const overallColumns = `id, id_play, overall`
+
+func scanOverall(row rowScanner) (domain.Overall, error) {
+	var overall domain.Overall
+	err := row.Scan(&overall.ID, &overall.IDPlay, &overall.Overall)
+	return overall, err
+}
+
+const GetByIDPlayQuery = `SELECT ` + overallColumns + ` FROM overall WHERE id_play = $1;`
+
+func (repo *OverallRepository) GetByIDPlay(ctx context.Context, idUser uuid.UUID) (domain.Overall, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
 
-func (repo *OverallRepository) GetByIDPlay(idUser uuid.UUID) (overall domain.Overall, err error) {
-	rows, err := repo.DB.Query(GetByIDPlayQuery, idUser)
+	overall, err := scanOverall(repo.DB.QueryRowContext(ctx, GetByIDPlayQuery, idUser))
 	if err != nil {
-		return overall, err
+		return overall, notFoundOnNoRows(err, "overall for play %s not found", idUser)
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		if err := rows.Scan(
-			&overall.ID,
-			&overall.IDPlay,
-			&overall.Overall,
-		); err != nil {
-			return overall, err
-		}
-	}
-	if err := rows.Err(); err != nil {
-		return overall, err
-	}
-
 	return overall, nil
 }
 
 const CreateOverallQuery = `INSERT INTO overall (id, id_play, overall) VALUES ($1, $2, $3) RETURNING id;`
 
-func (repo *OverallRepository) Create(overall domain.OverallRequest) (uuid.UUID, error) {
+func (repo *OverallRepository) Create(ctx context.Context, overall domain.OverallRequest) (uuid.UUID, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
 	id := uuid.New()
-	err := repo.DB.QueryRow(CreateOverallQuery, id, overall.IDPlay, overall.Overall).Scan(&id)
+	err := repo.DB.QueryRowContext(ctx, CreateOverallQuery, id, overall.IDPlay, overall.Overall).Scan(&id)
 	if err != nil {
-		return id, err
+		return id, conflictOnUniqueViolation(err, "overall for play %s already exists", overall.IDPlay)
+	}
+	return id, nil
+}
+
+const UpsertOverallQuery = `INSERT INTO overall (id_play, overall) VALUES ($1, $2) ON CONFLICT (id_play) DO UPDATE SET overall = EXCLUDED.overall RETURNING id;`
+
+// Upsert creates or replaces the overall of a player in a single statement,
+// so two messages for the same player cannot both insert.
+func (repo *OverallRepository) Upsert(ctx context.Context, overall domain.OverallRequest) (uuid.UUID, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	var id uuid.UUID
+	err := repo.DB.QueryRowContext(ctx, UpsertOverallQuery, overall.IDPlay, overall.Overall).Scan(&id)
+	if err != nil {
+		return uuid.Nil, err
 	}
 	return id, nil
 }
 
 const UpdateOverallQuery = `UPDATE overall SET overall = $1 WHERE id_play = $2;`
 
-func (repo *OverallRepository) Update(overall domain.OverallRequest, idUser uuid.UUID) error {
-	_, err := repo.DB.Exec(UpdateOverallQuery, overall.Overall, idUser)
+func (repo *OverallRepository) Update(ctx context.Context, overall domain.OverallRequest, idUser uuid.UUID) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	_, err := repo.DB.ExecContext(ctx, UpdateOverallQuery, overall.Overall, idUser)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-const DeleteOverallQuery = `DELETE FROM overall WHERE id_user = $1;`
+const DeleteOverallQuery = `DELETE FROM overall WHERE id_play = $1;`
+
+func (repo *OverallRepository) Delete(ctx context.Context, idUser uuid.UUID) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
 
-func (repo *OverallRepository) Delete(idUser uuid.UUID) error {
-	_, err := repo.DB.Exec(DeleteOverallQuery, idUser)
+	_, err := repo.DB.ExecContext(ctx, DeleteOverallQuery, idUser)
 	if err != nil {
 		return err
 	}
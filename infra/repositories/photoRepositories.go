@@ -1,67 +1,77 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"rachao/internal/core/domain"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type PhotoRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
 }
 
 const CreatePhotoQuery = `INSERT INTO photo (id_play, photo) VALUES ($1, $2) RETURNING id;`
 
-func (repo *PhotoRepository) Create(idPlay uuid.UUID, photo []byte) (uuid.UUID, error) {
+func (repo *PhotoRepository) Create(ctx context.Context, idPlay uuid.UUID, photo []byte) (uuid.UUID, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
 	var id uuid.UUID
-	err := repo.DB.QueryRow(CreatePhotoQuery, idPlay, photo).Scan(&id)
+	err := repo.DB.QueryRowContext(ctx, CreatePhotoQuery, idPlay, photo).Scan(&id)
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, conflictOnUniqueViolation(err, "photo for play %s already exists", idPlay)
 	}
 	return id, nil
 }
 
-const GetPhotoByIDPlayQuery = `SELECT * FROM photo WHERE id_play = $1;`
+// photoColumns is the projection scanned by scanPhoto, in the same order.
+const photoColumns = `id, id_play, photo`
+
+func scanPhoto(row rowScanner) (domain.Photo, error) {
+	var photo domain.Photo
+	err := row.Scan(&photo.ID, &photo.IDPlay, &photo.Photo)
+	return photo, err
+}
+
+const GetPhotoByIDPlayQuery = `SELECT ` + photoColumns + ` FROM photo WHERE id_play = $1;`
+
+func (repo *PhotoRepository) GetByIDPlay(ctx context.Context, idPlay uuid.UUID) ([]domain.Photo, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
 
-func (repo *PhotoRepository) GetByIDPlay(idPlay uuid.UUID) ([]domain.Photo, error) {
-	rows, err := repo.DB.Query(GetPhotoByIDPlayQuery, idPlay)
+	rows, err := repo.DB.QueryContext(ctx, GetPhotoByIDPlayQuery, idPlay)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var photos []domain.Photo
-	for rows.Next() {
-		var photo domain.Photo
-		if err := rows.Scan(&photo.ID, &photo.IDPlay, &photo.Photo); err != nil {
-			return nil, err
-		}
-		photos = append(photos, photo)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return photos, nil
+	return scanAll(rows, scanPhoto)
 }
 
 const UpdatePhotoQuery = `UPDATE photo SET photo = $1 WHERE id_play = $2;`
 
-func (repo *PhotoRepository) Update(idPlay uuid.UUID, photo []byte) error {
-	_, err := repo.DB.Exec(UpdatePhotoQuery, photo, idPlay)
+func (repo *PhotoRepository) Update(ctx context.Context, idPlay uuid.UUID, photo []byte) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	result, err := repo.DB.ExecContext(ctx, UpdatePhotoQuery, photo, idPlay)
 	if err != nil {
 		return err
 	}
-	return nil
+	return notFoundOnNoneAffected(result, "photo for play %s not found", idPlay)
 }
 
 const DeletePhotoQuery = `DELETE FROM photo WHERE id_play = $1;`
 
-func (repo *PhotoRepository) Delete(idPlay uuid.UUID) error {
-	_, err := repo.DB.Exec(DeletePhotoQuery, idPlay)
+func (repo *PhotoRepository) Delete(ctx context.Context, idPlay uuid.UUID) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	result, err := repo.DB.ExecContext(ctx, DeletePhotoQuery, idPlay)
 	if err != nil {
 		return err
 	}
-	return nil
+	return notFoundOnNoneAffected(result, "photo for play %s not found", idPlay)
 }
@@ -1,119 +1,193 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"rachao/internal/core/domain"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type PlayRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
 }
 
-const GetPlayAllQuery = `SELECT * FROM play WHERE active = true;`
+// playColumns is the projection scanned by scanPlay, in the same order.
+// Position and nation may be NULL in rows older than the API and read as 0.
+const playColumns = `id, name, coalesce(id_position, 0), coalesce(id_nation, 0), field, active, version`
 
-func (repo *PlayRepository) GetAll() ([]domain.Play, error) {
-	rows, err := repo.DB.Query(GetPlayAllQuery)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var plays []domain.Play
-	for rows.Next() {
-		var play domain.Play
-		if err := rows.Scan(&play.ID, &play.Name, &play.IDPosition, &play.IDNation, &play.Field, &play.Active); err != nil {
-			return nil, err
-		}
-		plays = append(plays, play)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return plays, nil
+func scanPlay(row rowScanner) (domain.Play, error) {
+	var play domain.Play
+	err := row.Scan(&play.ID, &play.Name, &play.IDPosition, &play.IDNation, &play.Field, &play.Active, &play.Version)
+	return play, err
 }
 
-const GetPlayAllByInactiveQuery = `SELECT * FROM play WHERE active = false;`
+const GetPlayAllQuery = `SELECT ` + playColumns + ` FROM play WHERE active = true;`
+
+func (repo *PlayRepository) GetAll(ctx context.Context) ([]domain.Play, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
 
-func (repo *PlayRepository) GetAllByInactive() ([]domain.Play, error) {
-	rows, err := repo.DB.Query(GetPlayAllByInactiveQuery)
+	rows, err := repo.DB.QueryContext(ctx, GetPlayAllQuery)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return scanAll(rows, scanPlay)
+}
 
-	var plays []domain.Play
-	for rows.Next() {
-		var play domain.Play
-		if err := rows.Scan(&play.ID, &play.Name, &play.IDPosition, &play.IDNation, &play.Field, &play.Active); err != nil {
-			return nil, err
-		}
-		plays = append(plays, play)
-	}
-	if err := rows.Err(); err != nil {
+const GetPlayAllByInactiveQuery = `SELECT ` + playColumns + ` FROM play WHERE active = false;`
+
+func (repo *PlayRepository) GetAllByInactive(ctx context.Context) ([]domain.Play, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetPlayAllByInactiveQuery)
+	if err != nil {
 		return nil, err
 	}
-
-	return plays, nil
+	return scanAll(rows, scanPlay)
 }
 
-const GetPlayByIDQuery = `SELECT * FROM play WHERE id = $1;`
+const GetPlayByIDQuery = `SELECT ` + playColumns + ` FROM play WHERE id = $1;`
 
-func (repo *PlayRepository) GetByID(id uuid.UUID) (domain.Play, error) {
-	row := repo.DB.QueryRow(GetPlayByIDQuery, id)
-	var play domain.Play
-	if err := row.Scan(&play.ID, &play.Name, &play.IDPosition, &play.IDNation, &play.Field, &play.Active); err != nil {
-		if err == sql.ErrNoRows {
-			return play, nil
-		}
-		return play, err
+func (repo *PlayRepository) GetByID(ctx context.Context, id uuid.UUID) (domain.Play, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	play, err := scanPlay(repo.DB.QueryRowContext(ctx, GetPlayByIDQuery, id))
+	if err != nil {
+		return play, notFoundOnNoRows(err, "play %s not found", id)
 	}
 	return play, nil
 }
 
 const CreatePlayQuery = `INSERT INTO play (name, id_position, id_nation, field, active) VALUES ($1, $2, $3, $4, $5) RETURNING id;`
 
-func (repo *PlayRepository) Create(play domain.CreatePlayRequest) (uuid.UUID, error) {
+func (repo *PlayRepository) Create(ctx context.Context, play domain.CreatePlayRequest) (uuid.UUID, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
 	var id uuid.UUID
-	err := repo.DB.QueryRow(CreatePlayQuery, play.Name, play.IDPosition, play.IDNation, play.Field, play.Active).Scan(&id)
+	err := repo.DB.QueryRowContext(ctx, CreatePlayQuery, play.Name, play.IDPosition, play.IDNation, play.Field, play.Active).Scan(&id)
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, conflictOnUniqueViolation(invalidOnDataError(err), "play %q already exists", play.Name)
 	}
 	return id, nil
 }
 
-const UpdatePlayQuery = `UPDATE play SET name = $1, id_position = $2, id_nation = $3, field = $4, active = $5 WHERE id = $6;`
+const UpdatePlayQuery = `UPDATE play SET name = $1, id_position = $2, id_nation = $3, field = $4, active = $5, version = version + 1
+  WHERE id = $6 AND ($7::int = 0 OR version = $7) RETURNING version;`
+
+const GetPlayVersionQuery = `SELECT version FROM play WHERE id = $1;`
+
+// Update saves the profile of a play, including its active flag, and bumps
+// its version. A version other than 0 must match the stored one; renaming to
+// a name another play already uses is a conflict.
+func (repo *PlayRepository) Update(ctx context.Context, id uuid.UUID, play domain.Play, version int) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	var newVersion int
+	err := repo.DB.QueryRowContext(ctx, UpdatePlayQuery, play.Name, play.IDPosition, play.IDNation, play.Field, play.Active, id, version).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, staleOrNotFound(ctx, repo.DB, GetPlayVersionQuery, id, version, "play %s", id)
+	}
+	if err != nil {
+		return 0, conflictOnUniqueViolation(invalidOnDataError(err), "play %q already exists", play.Name)
+	}
+	return newVersion, nil
+}
+
+const DeletePlayQuery = `UPDATE play SET active = false, version = version + 1 WHERE id = $1;`
+
+func (repo *PlayRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
 
-func (repo *PlayRepository) Update(id uuid.UUID, play domain.Play) error {
-	_, err := repo.DB.Exec(UpdatePlayQuery, play.Name, play.IDPosition, play.IDNation, play.Field, play.Active, id)
+	result, err := repo.DB.ExecContext(ctx, DeletePlayQuery, id)
 	if err != nil {
 		return err
 	}
-	return nil
+	return notFoundOnNoneAffected(result, "play %s not found", id)
 }
 
-const DeletePlayQuery = `UPDATE play SET active = false WHERE id = $1;`
+const ActivatePlayQuery = `UPDATE play SET active = true, version = version + 1 WHERE id = $1;`
 
-func (repo *PlayRepository) Delete(id uuid.UUID) error {
-	_, err := repo.DB.Exec(DeletePlayQuery, id)
+func (repo *PlayRepository) Activate(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	result, err := repo.DB.ExecContext(ctx, ActivatePlayQuery, id)
 	if err != nil {
 		return err
 	}
-	return nil
+	return notFoundOnNoneAffected(result, "play %s not found", id)
 }
 
-const GetPlayByNameQuery = `SELECT * FROM play WHERE name = $1;`
+// purgePlayQueries remove a play and every row that references it, children
+// first. Past matches keep their score but lose the player's line.
+var purgePlayQueries = []string{
+	`DELETE FROM rating_history WHERE id_play = $1;`,
+	`DELETE FROM match_player WHERE id_play = $1;`,
+	`DELETE FROM season_snapshot WHERE id_play = $1;`,
+	`DELETE FROM overall WHERE id_play = $1;`,
+	`DELETE FROM photo WHERE id_play = $1;`,
+	`DELETE FROM card WHERE id_play = $1;`,
+}
 
-func (repo *PlayRepository) GetByName(name string) (domain.Play, error) {
-	row := repo.DB.QueryRow(GetPlayByNameQuery, name)
-	var play domain.Play
-	if err := row.Scan(&play.ID, &play.Name, &play.IDPosition, &play.IDNation, &play.Field, &play.Active); err != nil {
-		if err == sql.ErrNoRows {
-			return play, nil
+const PurgePlayQuery = `DELETE FROM play WHERE id = $1;`
+
+// Purge permanently deletes a play with its card, photo, overall, match
+// lines, season snapshots and rating history in a single transaction, so
+// either all of them go or none does.
+func (repo *PlayRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range purgePlayQueries {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
 		}
-		return play, err
+	}
+	result, err := tx.ExecContext(ctx, PurgePlayQuery, id)
+	if err != nil {
+		return err
+	}
+	if err := notFoundOnNoneAffected(result, "play %s not found", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const GetPlayByNameQuery = `SELECT ` + playColumns + ` FROM play WHERE lower(name) = lower($1);`
+
+func (repo *PlayRepository) GetByName(ctx context.Context, name string) (domain.Play, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	play, err := scanPlay(repo.DB.QueryRowContext(ctx, GetPlayByNameQuery, name))
+	if err != nil {
+		return play, notFoundOnNoRows(err, "play %q not found", name)
 	}
 	return play, nil
 }
+
+const CountActivePlayQuery = `SELECT count(*) FROM play WHERE active = true;`
+
+func (repo *PlayRepository) CountActive(ctx context.Context) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	var count int
+	err := repo.DB.QueryRowContext(ctx, CountActivePlayQuery).Scan(&count)
+	return count, err
+}
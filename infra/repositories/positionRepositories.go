@@ -1,79 +1,99 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"rachao/internal/core/domain"
+	"time"
 )
 
 type PositionRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
 }
 
-const GetPositionAllQuery = `SELECT * FROM position ORDER BY name DESC;`
+// positionColumns is the projection scanned by scanPosition, in the same
+// order. The schema still allows NULL names and acronyms, which read as "".
+const positionColumns = `id, coalesce(name, ''), coalesce(acronym, ''), version`
 
-func (repo *PositionRepository) GetAll() ([]domain.Position, error) {
-	rows, err := repo.DB.Query(GetPositionAllQuery)
+func scanPosition(row rowScanner) (domain.Position, error) {
+	var position domain.Position
+	err := row.Scan(&position.ID, &position.Name, &position.Acronym, &position.Version)
+	return position, err
+}
+
+const GetPositionAllQuery = `SELECT ` + positionColumns + ` FROM position ORDER BY name DESC;`
+
+func (repo *PositionRepository) GetAll(ctx context.Context) ([]domain.Position, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetPositionAllQuery)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var positions []domain.Position
-	for rows.Next() {
-		var position domain.Position
-		if err := rows.Scan(&position.ID, &position.Name, &position.Acronym); err != nil {
-			return nil, err
-		}
-		positions = append(positions, position)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return positions, nil
+	return scanAll(rows, scanPosition)
 }
 
 const CreatePositionQuery = `INSERT INTO position (name, acronym) VALUES ($1, $2) RETURNING id;`
 
-func (repo *PositionRepository) Create(position domain.CreatePositionRequest) (int, error) {
+func (repo *PositionRepository) Create(ctx context.Context, position domain.CreatePositionRequest) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
 	var id int
-	err := repo.DB.QueryRow(CreatePositionQuery, position.Name, position.Acronym).Scan(&id)
+	err := repo.DB.QueryRowContext(ctx, CreatePositionQuery, position.Name, position.Acronym).Scan(&id)
 	if err != nil {
-		return 0, err
+		return 0, invalidOnDataError(err)
 	}
 	return id, nil
 }
 
-const GetPositionByIDQuery = `SELECT * FROM position WHERE id = $1;`
+const GetPositionByIDQuery = `SELECT ` + positionColumns + ` FROM position WHERE id = $1;`
 
-func (repo *PositionRepository) GetByID(id int) (domain.Position, error) {
-	row := repo.DB.QueryRow(GetPositionByIDQuery, id)
-	var position domain.Position
-	if err := row.Scan(&position.ID, &position.Name, &position.Acronym); err != nil {
-		if err == sql.ErrNoRows {
-			return position, nil
-		}
-		return position, err
+func (repo *PositionRepository) GetByID(ctx context.Context, id int) (domain.Position, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	position, err := scanPosition(repo.DB.QueryRowContext(ctx, GetPositionByIDQuery, id))
+	if err != nil {
+		return position, notFoundOnNoRows(err, "position %d not found", id)
 	}
 	return position, nil
 }
 
-const UpdatePositionQuery = `UPDATE position SET name = $1, acronym = $2 WHERE id = $3;`
+const UpdatePositionQuery = `UPDATE position SET name = $1, acronym = $2, version = version + 1
+  WHERE id = $3 AND ($4::int = 0 OR version = $4) RETURNING version;`
+
+const GetPositionVersionQuery = `SELECT version FROM position WHERE id = $1;`
 
-func (repo *PositionRepository) Update(id int, position domain.Position) error {
-	_, err := repo.DB.Exec(UpdatePositionQuery, position.Name, position.Acronym, id)
+// Update renames a position or changes its acronym, guarded by version
+// unless it is 0, and returns the bumped version.
+func (repo *PositionRepository) Update(ctx context.Context, id int, position domain.Position, version int) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	var newVersion int
+	err := repo.DB.QueryRowContext(ctx, UpdatePositionQuery, position.Name, position.Acronym, id, version).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, staleOrNotFound(ctx, repo.DB, GetPositionVersionQuery, id, version, "position %d", id)
+	}
 	if err != nil {
-		return err
+		return 0, invalidOnDataError(err)
 	}
-	return nil
+	return newVersion, nil
 }
 
 const DeletePositionQuery = `DELETE FROM position WHERE id = $1;`
 
-func (repo *PositionRepository) Delete(id int) error {
-	_, err := repo.DB.Exec(DeletePositionQuery, id)
+func (repo *PositionRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	result, err := repo.DB.ExecContext(ctx, DeletePositionQuery, id)
 	if err != nil {
 		return err
 	}
-	return nil
+	return notFoundOnNoneAffected(result, "position %d not found", id)
 }
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"rachao/internal/core/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PredictionRepository loads matches in the shape the prediction model
+// needs.
+type PredictionRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+// predictionLines is one row per player: the overall recorded with the match,
+// the rating the player brought into it (their latest one if the match has
+// not been rated yet, $1 if they have none) and their current card.
+const predictionLines = `SELECT m.id, m.home_goals, m.away_goals, mp.team, mp.overall,
+    coalesce(rh.rating_before, latest.rating_after, $1),
+    c.pac, c.sho, c.pas, c.dri, c.def, c.phy
+  FROM match m
+  JOIN match_player mp ON mp.id_match = m.id
+  LEFT JOIN rating_history rh ON rh.id_match = m.id AND rh.id_play = mp.id_play
+  LEFT JOIN LATERAL (
+    SELECT rating_after FROM rating_history WHERE id_play = mp.id_play ORDER BY id DESC LIMIT 1
+  ) latest ON true
+  LEFT JOIN card c ON c.id_play = mp.id_play`
+
+const GetPredictionMatchQuery = predictionLines + `
+  WHERE m.id = $2;`
+
+// GetPredictionScoreQuery reads a match whose players were all purged, which
+// GetPredictionMatchQuery returns no rows for.
+const GetPredictionScoreQuery = `SELECT id, home_goals, away_goals FROM match WHERE id = $1;`
+
+// GetPredictionHistoryQuery returns the finished matches other than $2 and,
+// once $2 is finished too, only those played before it, in the order ratings
+// replay them.
+const GetPredictionHistoryQuery = predictionLines + `
+  JOIN match target ON target.id = $2
+  WHERE m.home_goals IS NOT NULL AND m.id <> $2
+    AND (target.home_goals IS NULL OR (m.played_at, m.id) < (target.played_at, target.id))
+  ORDER BY m.played_at, m.id;`
+
+// GetMatch returns match id with its drafted players, none if they were all
+// purged.
+func (repo *PredictionRepository) GetMatch(ctx context.Context, id uuid.UUID) (domain.PredictionMatch, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetPredictionMatchQuery, domain.InitialRating, id)
+	if err != nil {
+		return domain.PredictionMatch{}, err
+	}
+	matches, err := scanPredictionMatches(rows)
+	if err != nil {
+		return domain.PredictionMatch{}, err
+	}
+	if len(matches) > 0 {
+		return matches[0], nil
+	}
+
+	var match domain.PredictionMatch
+	err = repo.DB.QueryRowContext(ctx, GetPredictionScoreQuery, id).Scan(&match.ID, &match.HomeGoals, &match.AwayGoals)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PredictionMatch{}, domain.NewNotFoundError("match %s not found", id)
+	}
+	if err != nil {
+		return domain.PredictionMatch{}, err
+	}
+	return match, nil
+}
+
+// GetHistory returns the finished matches the prediction of match id learns
+// from, oldest first: every other one while id is unfinished, and only the
+// ones played before it once it is finished.
+func (repo *PredictionRepository) GetHistory(ctx context.Context, id uuid.UUID) ([]domain.PredictionMatch, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetPredictionHistoryQuery, domain.InitialRating, id)
+	if err != nil {
+		return nil, err
+	}
+	return scanPredictionMatches(rows)
+}
+
+// scanPredictionMatches groups consecutive rows of the same match.
+func scanPredictionMatches(rows *sql.Rows) ([]domain.PredictionMatch, error) {
+	defer rows.Close()
+
+	var matches []domain.PredictionMatch
+	for rows.Next() {
+		var match domain.PredictionMatch
+		var player domain.PredictionPlayer
+		var stats [6]*int
+		err := rows.Scan(&match.ID, &match.HomeGoals, &match.AwayGoals, &player.Team, &player.Overall, &player.Rating,
+			&stats[0], &stats[1], &stats[2], &stats[3], &stats[4], &stats[5])
+		if err != nil {
+			return nil, err
+		}
+		if stats[0] != nil {
+			for _, stat := range stats {
+				player.Stats = append(player.Stats, *stat)
+			}
+		}
+		if n := len(matches); n == 0 || matches[n-1].ID != match.ID {
+			matches = append(matches, match)
+		}
+		last := &matches[len(matches)-1]
+		last.Players = append(last.Players, player)
+	}
+	return matches, rows.Err()
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"rachao/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+// TestPredictionProjections checks that a finished match learns only from
+// the matches played before it. The away player has no card.
+func TestPredictionProjections(t *testing.T) {
+	f := newFixture(t)
+	home := f.play(t, &testCard, 80)
+	away := f.play(t, nil, 0)
+	idSeason := f.season(t)
+	start := time.Now().UTC().Add(-time.Hour)
+	earlier := f.match(t, idSeason, start, home, away)
+	f.finish(t, earlier, 1, 0, home)
+	later := f.match(t, idSeason, start.Add(time.Minute), home, away)
+	f.finish(t, later, 0, 0, home)
+	pending := f.match(t, idSeason, start.Add(2*time.Minute), home, away)
+
+	predictions := PredictionRepository{DB: f.db, Timeout: testTimeout}
+	prediction := must(predictions.GetMatch(f.ctx, pending))(t)
+	if prediction.HomeGoals != nil || len(prediction.Players) != 2 {
+		t.Fatalf("prediction match = %+v", prediction)
+	}
+	for _, p := range prediction.Players {
+		if (p.Team == domain.TeamAway) != (p.Stats == nil) {
+			t.Errorf("prediction player = %+v", p)
+		}
+	}
+
+	contains := func(history []domain.PredictionMatch, id uuid.UUID) bool {
+		for _, match := range history {
+			if match.ID == id {
+				return true
+			}
+		}
+		return false
+	}
+	history := must(predictions.GetHistory(f.ctx, pending))(t)
+	if !contains(history, earlier) || !contains(history, later) || contains(history, pending) {
+		t.Errorf("unfinished match history = %+v", history)
+	}
+	history = must(predictions.GetHistory(f.ctx, earlier))(t)
+	if contains(history, earlier) || contains(history, later) {
+		t.Errorf("finished match history = %+v", history)
+	}
+	history = must(predictions.GetHistory(f.ctx, later))(t)
+	if !contains(history, earlier) || contains(history, later) {
+		t.Errorf("finished match history = %+v", history)
+	}
+}
+
+// TestPredictionMatchWithoutPlayers checks that a match whose players were
+// all purged is still found, with no players, unlike a missing one.
+func TestPredictionMatchWithoutPlayers(t *testing.T) {
+	f := newFixture(t)
+	home := f.play(t, &testCard, 80)
+	away := f.play(t, nil, 0)
+	id := f.match(t, f.season(t), time.Now().UTC(), home, away)
+	f.finish(t, id, 2, 1, home)
+
+	plays := PlayRepository{DB: f.db, Timeout: testTimeout}
+	for _, play := range []uuid.UUID{home, away} {
+		if err := plays.Purge(f.ctx, play); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	predictions := PredictionRepository{DB: f.db, Timeout: testTimeout}
+	match := must(predictions.GetMatch(f.ctx, id))(t)
+	if match.ID != id || match.HomeGoals == nil || *match.HomeGoals != 2 || len(match.Players) != 0 {
+		t.Errorf("match without players = %+v", match)
+	}
+	if _, err := predictions.GetMatch(f.ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("missing match: err = %v, want ErrNotFound", err)
+	}
+}
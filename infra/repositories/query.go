@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"rachao/infra/metrics"
+	"rachao/infra/tracing"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startQuery bounds a repository call with timeout and returns the cancel
+// func to defer, which also ends the call's span and records its duration,
+// both named after the calling method (e.g. PlayRepository.GetAll). A zero
+// timeout leaves the caller's deadline, if any, as the only limit.
+func startQuery(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	repository, operation := caller()
+	start := time.Now()
+	ctx, span := tracing.Tracer().Start(ctx, repository+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", operation),
+		),
+	)
+
+	var cancel context.CancelFunc
+	if timeout <= 0 {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {
+		cancel()
+		span.End()
+		metrics.QueryDuration.WithLabelValues(repository, operation).Observe(time.Since(start).Seconds())
+	}
+}
+
+// caller splits "rachao/infra/repositories.(*PlayRepository).GetAll" into
+// its receiver and method names.
+func caller() (string, string) {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown", "unknown"
+	}
+	name := runtime.FuncForPC(pc).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	parts := strings.Split(name, ".")
+	if len(parts) < 3 {
+		return "unknown", parts[len(parts)-1]
+	}
+	return strings.Trim(parts[1], "(*)"), parts[2]
+}
@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"rachao/internal/core/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type RatingRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+	// RebuildTimeout bounds Rebuild, which replays the whole history and
+	// outgrows Timeout as matches accumulate.
+	RebuildTimeout time.Duration
+}
+
+// GetRatingAllQuery lists active players with their latest rating, or the
+// initial one ($1) when they have no finished match yet.
+const GetRatingAllQuery = `SELECT p.id, p.name, coalesce(h.rating_after, $1), coalesce(h.games, 0), o.overall
+  FROM play p
+  LEFT JOIN overall o ON o.id_play = p.id
+  LEFT JOIN LATERAL (
+    SELECT rating_after, count(*) OVER () AS games
+    FROM rating_history
+    WHERE id_play = p.id
+    ORDER BY id DESC
+    LIMIT 1
+  ) h ON true
+  WHERE p.active = true
+  ORDER BY 3 DESC, p.name;`
+
+func (repo *RatingRepository) GetAll(ctx context.Context) ([]domain.Rating, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetRatingAllQuery, domain.InitialRating)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, func(row rowScanner) (domain.Rating, error) {
+		var rating domain.Rating
+		err := row.Scan(&rating.IDPlay, &rating.Name, &rating.Rating, &rating.Games, &rating.Overall)
+		return rating, err
+	})
+}
+
+const GetRatingHistoryQuery = `SELECT id_play, id_match, played_at, rating_before, rating_after
+  FROM rating_history
+  WHERE id_play = $1
+  ORDER BY id;`
+
+func (repo *RatingRepository) GetHistory(ctx context.Context, idPlay uuid.UUID) ([]domain.RatingChange, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetRatingHistoryQuery, idPlay)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanRatingChange)
+}
+
+func scanRatingChange(row rowScanner) (domain.RatingChange, error) {
+	var change domain.RatingChange
+	err := row.Scan(&change.IDPlay, &change.IDMatch, &change.PlayedAt, &change.Before, &change.After)
+	return change, err
+}
+
+// GetFinishedMatchesQuery returns one row per player of every finished
+// match, oldest match first.
+const GetFinishedMatchesQuery = `SELECT m.id, m.id_season, m.id_modality, m.played_at, m.home_goals, m.away_goals, m.version, mp.id_play, mp.team
+  FROM match m
+  JOIN match_player mp ON mp.id_match = m.id
+  WHERE m.home_goals IS NOT NULL
+  ORDER BY m.played_at, m.id;`
+
+const LockRatingHistoryQuery = `LOCK TABLE rating_history IN EXCLUSIVE MODE;`
+
+const ClearRatingHistoryQuery = `DELETE FROM rating_history;`
+
+const InsertRatingHistoryQuery = `INSERT INTO rating_history (id_play, id_match, played_at, rating_before, rating_after)
+  SELECT * FROM unnest($1::uuid[], $2::uuid[], $3::timestamptz[], $4::float8[], $5::float8[]);`
+
+// Rebuild replaces the whole rating history with what compute derives from
+// the finished matches, and returns how many changes it stored. The table is
+// locked for the duration so concurrent rebuilds run one after the other,
+// each from the latest results. It is bounded by RebuildTimeout rather than
+// Timeout.
+func (repo *RatingRepository) Rebuild(ctx context.Context, compute func([]domain.Match) []domain.RatingChange) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.RebuildTimeout)
+	defer cancel()
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, LockRatingHistoryQuery); err != nil {
+		return 0, err
+	}
+	matches, err := finishedMatches(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	changes := compute(matches)
+
+	if _, err := tx.ExecContext(ctx, ClearRatingHistoryQuery); err != nil {
+		return 0, err
+	}
+	if err := insertRatingChanges(ctx, tx, changes); err != nil {
+		return 0, err
+	}
+	return len(changes), tx.Commit()
+}
+
+func insertRatingChanges(ctx context.Context, tx *sql.Tx, changes []domain.RatingChange) error {
+	players := make([]string, len(changes))
+	ids := make([]string, len(changes))
+	playedAt := make([]string, len(changes))
+	before := make([]float64, len(changes))
+	after := make([]float64, len(changes))
+	for i, change := range changes {
+		players[i], ids[i] = change.IDPlay.String(), change.IDMatch.String()
+		playedAt[i] = change.PlayedAt.Format(time.RFC3339Nano)
+		before[i], after[i] = change.Before, change.After
+	}
+	_, err := tx.ExecContext(ctx, InsertRatingHistoryQuery,
+		pq.Array(players), pq.Array(ids), pq.Array(playedAt), pq.Array(before), pq.Array(after))
+	return err
+}
+
+// RatedSinceQuery tells whether rating_history holds the match at ($1, $2)
+// or any played after it, in the order GetFinishedMatchesQuery replays them.
+const RatedSinceQuery = `SELECT EXISTS (SELECT 1 FROM rating_history WHERE (played_at, id_match) >= ($1, $2));`
+
+// GetCurrentRatingsQuery returns the latest rating of each of the players in
+// $1 that has one.
+const GetCurrentRatingsQuery = `SELECT DISTINCT ON (id_play) id_play, rating_after
+  FROM rating_history
+  WHERE id_play = ANY($1::uuid[])
+  ORDER BY id_play, id DESC;`
+
+// Append stores the rating changes rate derives for match from its players'
+// current ratings, provided match comes after every match already rated. It
+// reports false, storing nothing, when it does not: the history after it
+// would then have to be replayed with Rebuild.
+func (repo *RatingRepository) Append(ctx context.Context, match domain.Match, rate func(map[uuid.UUID]float64, domain.Match) []domain.RatingChange) (bool, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, LockRatingHistoryQuery); err != nil {
+		return false, err
+	}
+	var ratedSince bool
+	if err := tx.QueryRowContext(ctx, RatedSinceQuery, match.PlayedAt, match.ID).Scan(&ratedSince); err != nil {
+		return false, err
+	}
+	if ratedSince {
+		return false, nil
+	}
+
+	players := make([]string, len(match.Players))
+	for i, player := range match.Players {
+		players[i] = player.IDPlay.String()
+	}
+	rows, err := tx.QueryContext(ctx, GetCurrentRatingsQuery, pq.Array(players))
+	if err != nil {
+		return false, err
+	}
+	ratings := map[uuid.UUID]float64{}
+	for rows.Next() {
+		var idPlay uuid.UUID
+		var rating float64
+		if err := rows.Scan(&idPlay, &rating); err != nil {
+			rows.Close()
+			return false, err
+		}
+		ratings[idPlay] = rating
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	if err := insertRatingChanges(ctx, tx, rate(ratings, match)); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// finishedMatches groups the rows of GetFinishedMatchesQuery into matches
+// with their players.
+func finishedMatches(ctx context.Context, tx *sql.Tx) ([]domain.Match, error) {
+	rows, err := tx.QueryContext(ctx, GetFinishedMatchesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []domain.Match
+	for rows.Next() {
+		var match domain.Match
+		var player domain.MatchPlayer
+		err := rows.Scan(&match.ID, &match.IDSeason, &match.IDModality, &match.PlayedAt, &match.HomeGoals, &match.AwayGoals, &match.Version,
+			&player.IDPlay, &player.Team)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(matches); n == 0 || matches[n-1].ID != match.ID {
+			matches = append(matches, match)
+		}
+		last := &matches[len(matches)-1]
+		last.Players = append(last.Players, player)
+	}
+	return matches, rows.Err()
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"rachao/internal/core/domain"
+)
+
+// TestRatingProjections rebuilds the history from a finished match, then
+// appends a later one. The away player has no overall.
+func TestRatingProjections(t *testing.T) {
+	f := newFixture(t)
+	home := f.play(t, &testCard, 80)
+	away := f.play(t, nil, 0)
+	idSeason := f.season(t)
+	today := time.Now().UTC()
+	finished := f.match(t, idSeason, today, home, away)
+	f.finish(t, finished, 2, 1, home)
+
+	ratings := RatingRepository{DB: f.db, Timeout: testTimeout, RebuildTimeout: testTimeout}
+	if count := must(ratings.Rebuild(f.ctx, domain.ComputeRatings))(t); count < 2 {
+		t.Errorf("rating changes = %d", count)
+	}
+	if history := must(ratings.GetHistory(f.ctx, home))(t); len(history) != 1 || history[0].IDMatch != finished || history[0].After <= history[0].Before {
+		t.Errorf("rating history = %+v", history)
+	}
+	for _, rating := range must(ratings.GetAll(f.ctx))(t) {
+		if rating.IDPlay == away && (rating.Overall != nil || rating.Games != 1) {
+			t.Errorf("rating = %+v", rating)
+		}
+	}
+
+	matches := MatchRepository{DB: f.db, Timeout: testTimeout}
+	match := must(matches.GetByID(f.ctx, finished))(t)
+	if appended := must(ratings.Append(f.ctx, match, domain.RateMatch))(t); appended {
+		t.Errorf("appended a match that is already rated")
+	}
+	latest := must(matches.GetByID(f.ctx, f.match(t, idSeason, today.Add(time.Minute), home, away)))(t)
+	homeGoals, awayGoals := 1, 2
+	latest.HomeGoals, latest.AwayGoals = &homeGoals, &awayGoals
+	if appended := must(ratings.Append(f.ctx, latest, domain.RateMatch))(t); !appended {
+		t.Errorf("did not append the latest match")
+	}
+	if history := must(ratings.GetHistory(f.ctx, home))(t); len(history) != 2 || history[1].Before != history[0].After || history[1].After >= history[1].Before {
+		t.Errorf("appended rating history = %+v", history)
+	}
+}
@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"rachao/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+// These tests run every projection and scan mapper against the migrated
+// schema, so a column added, dropped or reordered on one side only fails
+// here rather than in production. Rows the API would never write, with NULLs
+// the schema still allows, are inserted directly.
+
+// unique suffixes name so tests sharing the schema do not collide on the
+// unique name indexes.
+func unique(name string) string {
+	return name + " " + uuid.NewString()[:8]
+}
+
+type fixture struct {
+	db       *sql.DB
+	ctx      context.Context
+	nation   int
+	position int
+	modality int
+}
+
+func newFixture(t *testing.T) fixture {
+	db := requireDB(t)
+	ctx := context.Background()
+	nations := NationRepository{DB: db, Timeout: testTimeout}
+	positions := PositionRepository{DB: db, Timeout: testTimeout}
+	modalities := ModalityRepository{DB: db, Timeout: testTimeout}
+	return fixture{
+		db:       db,
+		ctx:      ctx,
+		nation:   must(nations.Create(ctx, domain.CreateNationRequest{Name: unique("Brasil"), Acronym: "BRA"}))(t),
+		position: must(positions.Create(ctx, domain.CreatePositionRequest{Name: unique("Atacante"), Acronym: "ATA"}))(t),
+		modality: must(modalities.Create(ctx, domain.CreateModalityRequest{Name: unique("Society"), Amount_play: 2, Active: true}))(t),
+	}
+}
+
+// play creates an active player, with a card when stats is not nil and an
+// overall when overall is not zero.
+func (f fixture) play(t *testing.T, stats *domain.CardRequest, overall int) uuid.UUID {
+	t.Helper()
+	plays := PlayRepository{DB: f.db, Timeout: testTimeout}
+	id := must(plays.Create(f.ctx, domain.CreatePlayRequest{Name: unique("Jogador"), IDPosition: f.position, IDNation: f.nation, Field: true, Active: true}))(t)
+	if stats != nil {
+		cards := CardRepository{DB: f.db, Timeout: testTimeout}
+		must(cards.Create(f.ctx, id, *stats))(t)
+	}
+	if overall != 0 {
+		overalls := OverallRepository{DB: f.db, Timeout: testTimeout}
+		must(overalls.Create(f.ctx, domain.OverallRequest{IDPlay: id, Overall: overall}))(t)
+	}
+	return id
+}
+
+// season creates an open season from a week ago to a week ahead.
+func (f fixture) season(t *testing.T) int {
+	t.Helper()
+	seasons := SeasonRepository{DB: f.db, Timeout: testTimeout}
+	today := time.Now().UTC()
+	return must(seasons.Create(f.ctx, domain.CreateSeasonRequest{
+		Name:     unique("Temporada"),
+		StartsOn: today.AddDate(0, 0, -7).Format(domain.DateLayout),
+		EndsOn:   today.AddDate(0, 0, 7).Format(domain.DateLayout),
+	}))(t)
+}
+
+// match registers an unfinished match of home against away in idSeason.
+func (f fixture) match(t *testing.T, idSeason int, playedAt time.Time, home, away uuid.UUID) uuid.UUID {
+	t.Helper()
+	matches := MatchRepository{DB: f.db, Timeout: testTimeout}
+	return must(matches.Create(f.ctx, domain.CreateMatchRequest{IDSeason: idSeason, IDModality: f.modality, PlayedAt: playedAt, Home: []uuid.UUID{home}, Away: []uuid.UUID{away}}))(t)
+}
+
+// finish records the score of match id, every home goal scored by scorer.
+func (f fixture) finish(t *testing.T, id uuid.UUID, homeGoals, awayGoals int, scorer uuid.UUID) {
+	t.Helper()
+	matches := MatchRepository{DB: f.db, Timeout: testTimeout}
+	must(matches.RecordResult(f.ctx, id, domain.MatchResultRequest{
+		HomeGoals: &homeGoals,
+		AwayGoals: &awayGoals,
+		Players:   []domain.MatchPlayerResult{{IDPlay: scorer, Goals: homeGoals}},
+	}, 0))(t)
+}
+
+var testCard = domain.CardRequest{PAC: 80, SHO: 70, PAS: 60, DRI: 75, DEF: 40, PHY: 65}
+
+func TestLookupProjections(t *testing.T) {
+	f := newFixture(t)
+	nations := NationRepository{DB: f.db, Timeout: testTimeout}
+	positions := PositionRepository{DB: f.db, Timeout: testTimeout}
+	modalities := ModalityRepository{DB: f.db, Timeout: testTimeout}
+
+	nation := must(nations.GetByID(f.ctx, f.nation))(t)
+	if nation.ID != f.nation || nation.Acronym != "BRA" || nation.Version != 1 {
+		t.Errorf("nation = %+v", nation)
+	}
+	must(nations.GetAll(f.ctx))(t)
+	position := must(positions.GetByID(f.ctx, f.position))(t)
+	if position.ID != f.position || position.Acronym != "ATA" || position.Version != 1 {
+		t.Errorf("position = %+v", position)
+	}
+	must(positions.GetAll(f.ctx))(t)
+	modality := must(modalities.GetByID(f.ctx, f.modality))(t)
+	if modality.ID != f.modality || modality.Amount_play != 2 || !modality.Active || modality.Version != 1 {
+		t.Errorf("modality = %+v", modality)
+	}
+	if byName := must(modalities.GetByName(f.ctx, modality.Name))(t); byName.ID != f.modality {
+		t.Errorf("modality by name = %+v", byName)
+	}
+	must(modalities.GetAll(f.ctx))(t)
+	must(modalities.GetAllByInactive(f.ctx))(t)
+
+	attributes := AttributesRepository{DB: f.db, Timeout: testTimeout}
+	id := must(attributes.Create(f.ctx, domain.AttributesRequest{IDPosition: f.position, PAC: 10, SHO: 30, PAS: 20, DRI: 20, DEF: 10, PHY: 10}))(t)
+	weights := must(attributes.GetByIDPosition(f.ctx, f.position))(t)
+	if weights.ID != id || weights.SHO != 30 || weights.PHY != 10 || weights.Version != 1 {
+		t.Errorf("attributes = %+v", weights)
+	}
+	must(attributes.GetByIDAttributes(f.ctx, id))(t)
+	must(attributes.GetAll(f.ctx))(t)
+}
+
+func TestLookupProjectionsReadNulls(t *testing.T) {
+	f := newFixture(t)
+	var nationID, positionID, modalityID int
+	for _, row := range []struct {
+		query string
+		id    *int
+	}{
+		{`INSERT INTO nation (name, acronym) VALUES (NULL, NULL) RETURNING id`, &nationID},
+		{`INSERT INTO position (name, acronym) VALUES (NULL, NULL) RETURNING id`, &positionID},
+		{`INSERT INTO modality (name, amount_play, active) VALUES (NULL, NULL, NULL) RETURNING id`, &modalityID},
+	} {
+		if err := f.db.QueryRow(row.query).Scan(row.id); err != nil {
+			t.Fatalf("%s: %v", row.query, err)
+		}
+	}
+
+	nations := NationRepository{DB: f.db, Timeout: testTimeout}
+	if nation := must(nations.GetByID(f.ctx, nationID))(t); nation.Name != "" || nation.Acronym != "" {
+		t.Errorf("nation = %+v", nation)
+	}
+	positions := PositionRepository{DB: f.db, Timeout: testTimeout}
+	if position := must(positions.GetByID(f.ctx, positionID))(t); position.Name != "" || position.Acronym != "" {
+		t.Errorf("position = %+v", position)
+	}
+	modalities := ModalityRepository{DB: f.db, Timeout: testTimeout}
+	if modality := must(modalities.GetByID(f.ctx, modalityID))(t); modality.Name != "" || modality.Amount_play != 0 || modality.Active {
+		t.Errorf("modality = %+v", modality)
+	}
+}
+
+func TestPlayCardProjections(t *testing.T) {
+	f := newFixture(t)
+	id := f.play(t, &testCard, 72)
+	plays := PlayRepository{DB: f.db, Timeout: testTimeout}
+
+	play := must(plays.GetByID(f.ctx, id))(t)
+	if play.ID != id || play.IDPosition != f.position || play.IDNation != f.nation || !play.Field || !play.Active || play.Version != 1 {
+		t.Errorf("play = %+v", play)
+	}
+	if byName := must(plays.GetByName(f.ctx, play.Name))(t); byName.ID != id {
+		t.Errorf("play by name = %+v", byName)
+	}
+	must(plays.GetAll(f.ctx))(t)
+	must(plays.GetAllByInactive(f.ctx))(t)
+
+	cards := CardRepository{DB: f.db, Timeout: testTimeout}
+	card := must(cards.GetByIDPlay(f.ctx, id))(t)
+	if card.IDPlay != id || card.PAC != 80 || card.SHO != 70 || card.PAS != 60 || card.DRI != 75 || card.DEF != 40 || card.PHY != 65 || card.Version != 1 {
+		t.Errorf("card = %+v", card)
+	}
+	if byID := must(cards.GetByID(f.ctx, card.ID))(t); byID != card {
+		t.Errorf("card by id = %+v, want %+v", byID, card)
+	}
+
+	cardPlays := CardPlayRepository{DB: f.db, Timeout: testTimeout}
+	cardPlay := must(cardPlays.GetByID(f.ctx, id))(t)
+	if cardPlay.Play != play || cardPlay.Card != card {
+		t.Errorf("card play = %+v", cardPlay)
+	}
+	if byPosition := must(cardPlays.GetByIDPosition(f.ctx, f.position))(t); len(byPosition) != 1 {
+		t.Errorf("card plays by position = %+v", byPosition)
+	}
+	must(cardPlays.GetAll(f.ctx))(t)
+	must(cardPlays.GetAllByInactive(f.ctx))(t)
+
+	overalls := OverallRepository{DB: f.db, Timeout: testTimeout}
+	if overall := must(overalls.GetByIDPlay(f.ctx, id))(t); overall.IDPlay != id || overall.Overall != 72 {
+		t.Errorf("overall = %+v", overall)
+	}
+
+	photos := PhotoRepository{DB: f.db, Timeout: testTimeout}
+	must(photos.Create(f.ctx, id, []byte{0xff, 0xd8}))(t)
+	if photo := must(photos.GetByIDPlay(f.ctx, id))(t); len(photo) != 1 || photo[0].IDPlay != id || len(photo[0].Photo) != 2 {
+		t.Errorf("photo = %+v", photo)
+	}
+}
+
+func TestPlayCardProjectionsReadNulls(t *testing.T) {
+	f := newFixture(t)
+	id := uuid.New()
+	mustExec(t, f.db, `INSERT INTO play (id, name, id_position, id_nation) VALUES ($1, $2, NULL, NULL)`, id, unique("Legado"))
+	mustExec(t, f.db, `INSERT INTO card (id_play, pac, sho, pas, dri, def, phy) VALUES ($1, 1, 1, 1, 1, 1, 1)`, id)
+	mustExec(t, f.db, `INSERT INTO photo (id_play, photo) VALUES ($1, NULL)`, id)
+
+	plays := PlayRepository{DB: f.db, Timeout: testTimeout}
+	if play := must(plays.GetByID(f.ctx, id))(t); play.IDPosition != 0 || play.IDNation != 0 {
+		t.Errorf("play = %+v", play)
+	}
+	cardPlays := CardPlayRepository{DB: f.db, Timeout: testTimeout}
+	if cardPlay := must(cardPlays.GetByID(f.ctx, id))(t); cardPlay.Play.IDPosition != 0 || cardPlay.Play.IDNation != 0 {
+		t.Errorf("card play = %+v", cardPlay)
+	}
+	photos := PhotoRepository{DB: f.db, Timeout: testTimeout}
+	if photo := must(photos.GetByIDPlay(f.ctx, id))(t); len(photo) != 1 || photo[0].Photo != nil {
+		t.Errorf("photo = %+v", photo)
+	}
+}
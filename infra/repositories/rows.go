@@ -0,0 +1,28 @@
+package repositories
+
+import "database/sql"
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows, so one mapping
+// function per entity serves single-row and list queries alike.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAll maps every row with scan and closes rows. It returns a nil slice
+// when the query matched nothing.
+func scanAll[T any](rows *sql.Rows, scan func(rowScanner) (T, error)) ([]T, error) {
+	defer rows.Close()
+
+	var items []T
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"rachao/internal/core/domain"
+	"time"
+)
+
+type SeasonRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+// seasonColumns is the projection scanned by scanSeason, in the same order.
+const seasonColumns = `id, name, to_char(starts_on, 'YYYY-MM-DD'), to_char(ends_on, 'YYYY-MM-DD'), closed_at, version`
+
+func scanSeason(row rowScanner) (domain.Season, error) {
+	var season domain.Season
+	err := row.Scan(&season.ID, &season.Name, &season.StartsOn, &season.EndsOn, &season.ClosedAt, &season.Version)
+	return season, err
+}
+
+const GetSeasonAllQuery = `SELECT ` + seasonColumns + ` FROM season ORDER BY starts_on DESC;`
+
+func (repo *SeasonRepository) GetAll(ctx context.Context) ([]domain.Season, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetSeasonAllQuery)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanSeason)
+}
+
+const GetSeasonByIDQuery = `SELECT ` + seasonColumns + ` FROM season WHERE id = $1;`
+
+func (repo *SeasonRepository) GetByID(ctx context.Context, id int) (domain.Season, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	season, err := scanSeason(repo.DB.QueryRowContext(ctx, GetSeasonByIDQuery, id))
+	if err != nil {
+		return season, notFoundOnNoRows(err, "season %d not found", id)
+	}
+	return season, nil
+}
+
+const CreateSeasonQuery = `INSERT INTO season (name, starts_on, ends_on) VALUES ($1, $2, $3) RETURNING id;`
+
+func (repo *SeasonRepository) Create(ctx context.Context, season domain.CreateSeasonRequest) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	var id int
+	err := repo.DB.QueryRowContext(ctx, CreateSeasonQuery, season.Name, season.StartsOn, season.EndsOn).Scan(&id)
+	if err != nil {
+		return 0, conflictOnUniqueViolation(invalidOnDataError(err), "season %q already exists", season.Name)
+	}
+	return id, nil
+}
+
+const UpdateSeasonQuery = `UPDATE season SET name = $1, starts_on = $2, ends_on = $3, version = version + 1
+  WHERE id = $4 AND ($5::int = 0 OR version = $5) RETURNING version;`
+
+const GetSeasonVersionQuery = `SELECT version FROM season WHERE id = $1;`
+
+// Update changes the name and dates of a season, guarded by version unless it
+// is 0. The name must stay unique across seasons.
+func (repo *SeasonRepository) Update(ctx context.Context, id int, season domain.Season, version int) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	var newVersion int
+	err := repo.DB.QueryRowContext(ctx, UpdateSeasonQuery, season.Name, season.StartsOn, season.EndsOn, id, version).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, staleOrNotFound(ctx, repo.DB, GetSeasonVersionQuery, id, version, "season %d", id)
+	}
+	if err != nil {
+		return 0, conflictOnUniqueViolation(invalidOnDataError(err), "season %q already exists", season.Name)
+	}
+	return newVersion, nil
+}
+
+const CloseSeasonQuery = `UPDATE season SET closed_at = now(), version = version + 1
+  WHERE id = $1 AND closed_at IS NULL RETURNING version;`
+
+// SnapshotSeasonQuery freezes the card and overall of every active player.
+const SnapshotSeasonQuery = `INSERT INTO season_snapshot (id_season, id_play, pac, sho, pas, dri, def, phy, overall)
+  SELECT $1, c.id_play, c.pac, c.sho, c.pas, c.dri, c.def, c.phy, o.overall
+  FROM card c
+  JOIN play p ON p.id = c.id_play
+  LEFT JOIN overall o ON o.id_play = c.id_play
+  WHERE p.active = true;`
+
+// Close marks the season closed and snapshots the cards in one transaction,
+// returning the new version. Closing twice is a conflict.
+func (repo *SeasonRepository) Close(ctx context.Context, id int) (int, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var newVersion int
+	err = tx.QueryRowContext(ctx, CloseSeasonQuery, id).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		var version int
+		if err := tx.QueryRowContext(ctx, GetSeasonVersionQuery, id).Scan(&version); err != nil {
+			return 0, notFoundOnNoRows(err, "season %d not found", id)
+		}
+		return 0, domain.NewConflictError("season %d is already closed", id)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, SnapshotSeasonQuery, id); err != nil {
+		return 0, err
+	}
+	return newVersion, tx.Commit()
+}
+
+const GetSeasonSnapshotsQuery = `SELECT s.id_season, s.id_play, p.name, s.pac, s.sho, s.pas, s.dri, s.def, s.phy, s.overall
+  FROM season_snapshot s
+  JOIN play p ON p.id = s.id_play
+  WHERE s.id_season = $1
+  ORDER BY s.overall DESC NULLS LAST, p.name;`
+
+func (repo *SeasonRepository) GetSnapshots(ctx context.Context, id int) ([]domain.SeasonSnapshot, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetSeasonSnapshotsQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, func(row rowScanner) (domain.SeasonSnapshot, error) {
+		var s domain.SeasonSnapshot
+		err := row.Scan(&s.IDSeason, &s.IDPlay, &s.Name, &s.PAC, &s.SHO, &s.PAS, &s.DRI, &s.DEF, &s.PHY, &s.Overall)
+		return s, err
+	})
+}
+
+// GetSeasonStatsQuery aggregates the finished matches of a season per
+// player. team_overall is the mean overall of each side of each match.
+const GetSeasonStatsQuery = `WITH team AS (
+    SELECT id_match, team, avg(overall) AS team_overall
+    FROM match_player
+    GROUP BY id_match, team
+  ), result AS (
+    SELECT mp.id_play, mp.goals, mp.assists, t.team_overall,
+      sign(CASE mp.team WHEN 'home' THEN m.home_goals - m.away_goals ELSE m.away_goals - m.home_goals END) AS outcome
+    FROM match_player mp
+    JOIN match m ON m.id = mp.id_match
+    JOIN team t ON t.id_match = mp.id_match AND t.team = mp.team
+    WHERE m.id_season = $1 AND m.home_goals IS NOT NULL
+  )
+  SELECT r.id_play, p.name, count(*),
+    count(*) FILTER (WHERE r.outcome > 0),
+    count(*) FILTER (WHERE r.outcome = 0),
+    count(*) FILTER (WHERE r.outcome < 0),
+    sum(r.goals), sum(r.assists),
+    round(avg(r.team_overall), 1)::float8
+  FROM result r
+  JOIN play p ON p.id = r.id_play
+  GROUP BY r.id_play, p.name
+  ORDER BY 4 DESC, 7 DESC, p.name;`
+
+func (repo *SeasonRepository) GetStats(ctx context.Context, id int) ([]domain.SeasonPlayerStats, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	rows, err := repo.DB.QueryContext(ctx, GetSeasonStatsQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, func(row rowScanner) (domain.SeasonPlayerStats, error) {
+		var s domain.SeasonPlayerStats
+		err := row.Scan(&s.IDPlay, &s.Name, &s.Games, &s.Wins, &s.Draws, &s.Losses, &s.Goals, &s.Assists, &s.AverageTeamOverall)
+		return s, err
+	})
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"rachao/internal/core/domain"
+)
+
+// TestSeasonProjections plays one match of a season, leaves another
+// unfinished and closes the season. The away player has no card and no
+// overall, so the LEFT JOINs yield NULLs.
+func TestSeasonProjections(t *testing.T) {
+	f := newFixture(t)
+	home := f.play(t, &testCard, 80)
+	away := f.play(t, nil, 0)
+
+	seasons := SeasonRepository{DB: f.db, Timeout: testTimeout}
+	idSeason := f.season(t)
+	season := must(seasons.GetByID(f.ctx, idSeason))(t)
+	today := time.Now().UTC()
+	if season.ClosedAt != nil || season.Version != 1 || season.StartsOn != today.AddDate(0, 0, -7).Format(domain.DateLayout) {
+		t.Errorf("season = %+v", season)
+	}
+	must(seasons.GetAll(f.ctx))(t)
+
+	matches := MatchRepository{DB: f.db, Timeout: testTimeout}
+	finished := f.match(t, idSeason, today, home, away)
+	pending := f.match(t, idSeason, today, home, away)
+
+	match := must(matches.GetByID(f.ctx, pending))(t)
+	if match.HomeGoals != nil || match.AwayGoals != nil || len(match.Players) != 2 {
+		t.Fatalf("unfinished match = %+v", match)
+	}
+	for _, player := range match.Players {
+		if (player.IDPlay == away) != (player.Overall == nil) {
+			t.Errorf("match player = %+v", player)
+		}
+	}
+
+	f.finish(t, finished, 2, 1, home)
+	match = must(matches.GetByID(f.ctx, finished))(t)
+	if !match.Finished() || *match.HomeGoals != 2 || match.Version != 2 {
+		t.Errorf("finished match = %+v", match)
+	}
+	if bySeason := must(matches.GetBySeason(f.ctx, idSeason))(t); len(bySeason) != 2 {
+		t.Errorf("season matches = %+v", bySeason)
+	}
+
+	stats := must(seasons.GetStats(f.ctx, idSeason))(t)
+	if len(stats) != 2 {
+		t.Fatalf("season stats = %+v", stats)
+	}
+	for _, s := range stats {
+		if s.Games != 1 || (s.IDPlay == away) != (s.AverageTeamOverall == nil) {
+			t.Errorf("season stats = %+v", s)
+		}
+	}
+
+	must(seasons.Close(f.ctx, idSeason))(t)
+	if season := must(seasons.GetByID(f.ctx, idSeason))(t); season.ClosedAt == nil {
+		t.Errorf("closed season = %+v", season)
+	}
+	for _, snapshot := range must(seasons.GetSnapshots(f.ctx, idSeason))(t) {
+		if snapshot.IDPlay == home && (snapshot.Overall == nil || *snapshot.Overall != 80 || snapshot.PAC != 80) {
+			t.Errorf("snapshot = %+v", snapshot)
+		}
+	}
+}
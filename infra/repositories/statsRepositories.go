@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"rachao/internal/core/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// StatsRepository computes statistics from the recorded match results. All
+// aggregation happens in SQL.
+type StatsRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+// playerResults is one row per player per finished match, with outcome 1, 0
+// or -1 from that player's side. $1 is a season, 0 for all of them.
+const playerResults = `SELECT mp.id_play, mp.id_match, mp.team, mp.goals, mp.assists, m.played_at,
+      sign(CASE mp.team WHEN 'home' THEN m.home_goals - m.away_goals ELSE m.away_goals - m.home_goals END)::int AS outcome
+    FROM match_player mp
+    JOIN match m ON m.id = mp.id_match
+    WHERE m.home_goals IS NOT NULL AND ($1::int = 0 OR m.id_season = $1)`
+
+// leaderboardValues maps each result-based metric to its SQL expression over
+// the per-player aggregate.
+var leaderboardValues = map[string]string{
+	domain.MetricGoals:   `a.goals`,
+	domain.MetricAssists: `a.assists`,
+	domain.MetricWins:    `a.wins`,
+	domain.MetricWinRate: `round(a.wins::numeric / a.games, 3)`,
+}
+
+const leaderboardFromResults = `WITH result AS (` + playerResults + `
+  ), agg AS (
+    SELECT id_play, count(*) AS games, count(*) FILTER (WHERE outcome > 0) AS wins,
+      sum(goals) AS goals, sum(assists) AS assists
+    FROM result
+    GROUP BY id_play
+    HAVING count(*) >= $2
+  ), ranked AS (
+    SELECT a.id_play, a.games, (%s)::float8 AS value FROM agg a
+  )
+  SELECT rank() OVER (ORDER BY r.value DESC), r.id_play, p.name, r.games, r.value
+  FROM ranked r
+  JOIN play p ON p.id = r.id_play
+  ORDER BY r.value DESC, p.name
+  LIMIT $3;`
+
+// leaderboardByOverall ranks current overalls of active players, or those
+// frozen at the close of season $1.
+const leaderboardByOverall = `WITH result AS (` + playerResults + `
+  ), games AS (
+    SELECT id_play, count(*) AS games FROM result GROUP BY id_play
+  ), rating AS (
+    SELECT o.id_play, o.overall FROM overall o JOIN play p ON p.id = o.id_play
+    WHERE $1::int = 0 AND p.active = true
+    UNION ALL
+    SELECT s.id_play, s.overall FROM season_snapshot s
+    WHERE $1::int <> 0 AND s.id_season = $1 AND s.overall IS NOT NULL
+  )
+  SELECT rank() OVER (ORDER BY r.overall DESC), r.id_play, p.name, coalesce(g.games, 0), r.overall::float8
+  FROM rating r
+  JOIN play p ON p.id = r.id_play
+  LEFT JOIN games g ON g.id_play = r.id_play
+  WHERE coalesce(g.games, 0) >= $2
+  ORDER BY r.overall DESC, p.name
+  LIMIT $3;`
+
+// Leaderboard ranks players by query.Metric, best first.
+func (repo *StatsRepository) Leaderboard(ctx context.Context, query domain.LeaderboardQuery) ([]domain.LeaderboardEntry, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	statement := leaderboardByOverall
+	if value, ok := leaderboardValues[query.Metric]; ok {
+		statement = fmt.Sprintf(leaderboardFromResults, value)
+	}
+	rows, err := repo.DB.QueryContext(ctx, statement, query.Season, query.MinGames, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, func(row rowScanner) (domain.LeaderboardEntry, error) {
+		var entry domain.LeaderboardEntry
+		err := row.Scan(&entry.Rank, &entry.IDPlay, &entry.Name, &entry.Games, &entry.Value)
+		return entry, err
+	})
+}
+
+const PlayerTotalsQuery = `WITH result AS (` + playerResults + ` AND mp.id_play = $2)
+  SELECT count(*),
+    count(*) FILTER (WHERE outcome > 0),
+    count(*) FILTER (WHERE outcome = 0),
+    count(*) FILTER (WHERE outcome < 0),
+    round(count(*) FILTER (WHERE outcome > 0)::numeric / nullif(count(*), 0), 3)::float8,
+    coalesce(sum(goals), 0), coalesce(sum(assists), 0),
+    round(avg(goals), 2)::float8, round(avg(assists), 2)::float8
+  FROM result;`
+
+// PlayerStreaksQuery splits the player's matches into runs of the same
+// outcome (gaps and islands) and reports the longest win and loss runs and
+// the run the player is currently on.
+const PlayerStreaksQuery = `WITH result AS (` + playerResults + ` AND mp.id_play = $2
+  ), run AS (
+    SELECT outcome, played_at,
+      row_number() OVER (ORDER BY played_at, id_match)
+        - row_number() OVER (PARTITION BY outcome ORDER BY played_at, id_match) AS grp
+    FROM result
+  ), streak AS (
+    SELECT outcome, count(*) AS length, max(played_at) AS last_played
+    FROM run
+    GROUP BY outcome, grp
+  )
+  SELECT coalesce(max(length) FILTER (WHERE outcome > 0), 0),
+    coalesce(max(length) FILTER (WHERE outcome < 0), 0),
+    coalesce((SELECT outcome FROM streak ORDER BY last_played DESC LIMIT 1), 0),
+    coalesce((SELECT length FROM streak ORDER BY last_played DESC LIMIT 1), 0)
+  FROM streak;`
+
+// PlayerTeammatesQuery compares the player's win rate with and without each
+// team mate: the ones in $3 or, when $3 is empty, everyone who shared a side
+// with them.
+const PlayerTeammatesQuery = `WITH result AS (` + playerResults + ` AND mp.id_play = $2
+  ), mate AS (
+    SELECT unnest($3::uuid[]) AS id_play WHERE coalesce(cardinality($3::uuid[]), 0) > 0
+    UNION
+    SELECT o.id_play FROM result r
+    JOIN match_player o ON o.id_match = r.id_match AND o.team = r.team AND o.id_play <> $2
+    WHERE coalesce(cardinality($3::uuid[]), 0) = 0
+  ), paired AS (
+    SELECT t.id_play, o.id_play IS NOT NULL AS together, r.outcome
+    FROM mate t
+    CROSS JOIN result r
+    LEFT JOIN match_player o ON o.id_match = r.id_match AND o.team = r.team AND o.id_play = t.id_play
+  )
+  SELECT pa.id_play, p.name,
+    count(*) FILTER (WHERE together),
+    round(count(*) FILTER (WHERE together AND outcome > 0)::numeric / nullif(count(*) FILTER (WHERE together), 0), 3)::float8,
+    count(*) FILTER (WHERE NOT together),
+    round(count(*) FILTER (WHERE NOT together AND outcome > 0)::numeric / nullif(count(*) FILTER (WHERE NOT together), 0), 3)::float8
+  FROM paired pa
+  JOIN play p ON p.id = pa.id_play
+  GROUP BY pa.id_play, p.name
+  ORDER BY 3 DESC, p.name;`
+
+// PlayerStats returns the totals, streaks and team mate comparison of a
+// player over the finished matches selected by query.
+func (repo *StatsRepository) PlayerStats(ctx context.Context, id uuid.UUID, query domain.PlayerStatsQuery) (domain.PlayerStats, error) {
+	ctx, cancel := startQuery(ctx, repo.Timeout)
+	defer cancel()
+
+	stats := domain.PlayerStats{IDPlay: id}
+	err := repo.DB.QueryRowContext(ctx, PlayerTotalsQuery, query.Season, id).Scan(
+		&stats.Games, &stats.Wins, &stats.Draws, &stats.Losses, &stats.WinRate,
+		&stats.Goals, &stats.Assists, &stats.GoalsPerMatch, &stats.AssistsPerMatch,
+	)
+	if err != nil {
+		return stats, err
+	}
+
+	var outcome int
+	err = repo.DB.QueryRowContext(ctx, PlayerStreaksQuery, query.Season, id).Scan(
+		&stats.LongestWinStreak, &stats.LongestLossStreak, &outcome, &stats.CurrentStreak.Length,
+	)
+	if err != nil {
+		return stats, err
+	}
+	if stats.CurrentStreak.Length > 0 {
+		stats.CurrentStreak.Outcome = outcomeName(outcome)
+	}
+
+	rows, err := repo.DB.QueryContext(ctx, PlayerTeammatesQuery, query.Season, id, pq.Array(query.Teammates))
+	if err != nil {
+		return stats, err
+	}
+	stats.Teammates, err = scanAll(rows, func(row rowScanner) (domain.TeammateStats, error) {
+		var t domain.TeammateStats
+		err := row.Scan(&t.IDPlay, &t.Name, &t.GamesWith, &t.WinRateWith, &t.GamesWithout, &t.WinRateWithout)
+		return t, err
+	})
+	return stats, err
+}
+
+func outcomeName(outcome int) string {
+	switch {
+	case outcome > 0:
+		return domain.OutcomeWin
+	case outcome < 0:
+		return domain.OutcomeLoss
+	default:
+		return domain.OutcomeDraw
+	}
+}
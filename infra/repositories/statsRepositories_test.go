@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"rachao/internal/core/domain"
+)
+
+func TestStatsProjections(t *testing.T) {
+	f := newFixture(t)
+	home := f.play(t, &testCard, 80)
+	away := f.play(t, nil, 0)
+	idSeason := f.season(t)
+	f.finish(t, f.match(t, idSeason, time.Now().UTC(), home, away), 2, 1, home)
+
+	statsRepo := StatsRepository{DB: f.db, Timeout: testTimeout}
+	for _, metric := range []string{domain.MetricGoals, domain.MetricAssists, domain.MetricWins, domain.MetricWinRate, domain.MetricOverall} {
+		must(statsRepo.Leaderboard(f.ctx, domain.LeaderboardQuery{Metric: metric, Season: idSeason, Limit: 10}))(t)
+		must(statsRepo.Leaderboard(f.ctx, domain.LeaderboardQuery{Metric: metric, Limit: 10}))(t)
+	}
+	player := must(statsRepo.PlayerStats(f.ctx, home, domain.PlayerStatsQuery{Season: idSeason}))(t)
+	if player.Games != 1 || player.Wins != 1 || player.Goals != 2 || player.WinRate == nil || player.CurrentStreak.Outcome != domain.OutcomeWin || len(player.Teammates) != 0 {
+		t.Errorf("player stats = %+v", player)
+	}
+	idle := f.play(t, nil, 0)
+	if player := must(statsRepo.PlayerStats(f.ctx, idle, domain.PlayerStatsQuery{Teammates: []string{home.String()}}))(t); player.Games != 0 || player.WinRate != nil || player.GoalsPerMatch != nil {
+		t.Errorf("idle player stats = %+v", player)
+	}
+}
@@ -0,0 +1,24 @@
+package adapters
+
+import (
+	"crypto/subtle"
+	"rachao/internal/core/domain"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminOnly lets through requests carrying "Authorization: Bearer <token>".
+// With an empty token every request is refused, so admin routes stay closed
+// until ADMIN_TOKEN is configured.
+func AdminOnly(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			c.Error(domain.NewUnauthorizedError("admin token required"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,144 @@
+package adapters
+
+import (
+	"context"
+	"rachao/internal/core/domain"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) createAttributes(c *gin.Context) {
+	var attributes domain.AttributesRequest
+	if !bindJSON(c, &attributes) {
+		return
+	}
+	id, err := ga.Attributes.Create(c.Request.Context(), attributes)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(201, gin.H{"data": id})
+}
+
+func (ga *GinAdapter) getAllAttributes(c *gin.Context) {
+	attributes, err := ga.Attributes.GetAll(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, attributes)
+}
+
+func (ga *GinAdapter) getAttributes(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	attributes, err := ga.Attributes.GetByIDAttributes(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	setETag(c, attributes.Version)
+	c.JSON(200, gin.H{"data": attributes})
+}
+
+func (ga *GinAdapter) getAttributesByPosition(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	attributes, err := ga.Attributes.GetByIDPosition(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"data": attributes})
+}
+
+func (ga *GinAdapter) updateAttributes(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	var job *domain.RecalculateJob
+	write := func(ctx context.Context, attributes domain.AttributesRequest, version int) (int, error) {
+		newVersion, started, err := ga.Attributes.Update(ctx, id, attributes, version)
+		job = started
+		return newVersion, err
+	}
+	if replaceVersioned(c, write) {
+		c.JSON(200, gin.H{"message": "Attributes updated successfully", "recalculation": job})
+	}
+}
+
+// patchAttributes moves weight between stats of a position. The merged
+// weights must still add up to AttributesWeightTotal, so a patch usually
+// changes two of them at once.
+func (ga *GinAdapter) patchAttributes(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	var job *domain.RecalculateJob
+	write := func(ctx context.Context, attributes domain.AttributesRequest, version int) (int, error) {
+		newVersion, started, err := ga.Attributes.Update(ctx, id, attributes, version)
+		job = started
+		return newVersion, err
+	}
+	current := func(ctx context.Context) (domain.AttributesRequest, int, error) {
+		attributes, err := ga.Attributes.GetByIDAttributes(ctx, id)
+		return domain.AttributesRequest{
+			IDPosition: attributes.IDPosition,
+			PAC:        attributes.PAC,
+			SHO:        attributes.SHO,
+			PAS:        attributes.PAS,
+			DRI:        attributes.DRI,
+			DEF:        attributes.DEF,
+			PHY:        attributes.PHY,
+		}, attributes.Version, err
+	}
+	if patchVersioned(c, current, write) {
+		c.JSON(200, gin.H{"message": "Attributes updated successfully", "recalculation": job})
+	}
+}
+
+func (ga *GinAdapter) deleteAttributes(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ga.Attributes.Delete(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Attributes deleted successfully"})
+}
+
+func (ga *GinAdapter) recalculateOveralls(c *gin.Context) {
+	idPosition, err := strconv.Atoi(c.Query("position"))
+	if err != nil {
+		c.Error(domain.NewFieldError("position", "invalid position format"))
+		return
+	}
+	job, err := ga.Recalculate.StartJob(c.Request.Context(), idPosition)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(202, gin.H{"data": job})
+}
+
+func (ga *GinAdapter) getRecalculateJob(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	job, err := ga.Recalculate.GetJob(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"data": job})
+}
@@ -0,0 +1,109 @@
+package adapters
+
+import (
+	"context"
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) getCard(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	card, err := ga.CardUseCase.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	setETag(c, card.Version)
+	c.JSON(200, gin.H{"data": card})
+}
+
+func (ga *GinAdapter) createCard(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	var card domain.CardRequest
+	if !bindJSON(c, &card) {
+		return
+	}
+	cardID, err := ga.CardUseCase.Create(c.Request.Context(), id, card)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(201, gin.H{"id": cardID})
+}
+
+func (ga *GinAdapter) updateCard(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	write := func(ctx context.Context, card domain.CardRequest, version int) (int, error) {
+		return ga.CardUseCase.Update(ctx, id, card, version)
+	}
+	if replaceVersioned(c, write) {
+		c.JSON(200, gin.H{"message": "Card updated successfully"})
+	}
+}
+
+// patchCard adjusts single stats of a card, e.g. {"pac": 80}; the stats left
+// out keep their value and the overall is recalculated from the result.
+func (ga *GinAdapter) patchCard(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	current := func(ctx context.Context) (domain.CardRequest, int, error) {
+		card, err := ga.CardUseCase.GetByID(ctx, id)
+		return domain.CardRequest{
+			PAC: card.PAC,
+			SHO: card.SHO,
+			PAS: card.PAS,
+			DRI: card.DRI,
+			DEF: card.DEF,
+			PHY: card.PHY,
+		}, card.Version, err
+	}
+	write := func(ctx context.Context, card domain.CardRequest, version int) (int, error) {
+		return ga.CardUseCase.Update(ctx, id, card, version)
+	}
+	if patchVersioned(c, current, write) {
+		c.JSON(200, gin.H{"message": "Card updated successfully"})
+	}
+}
+
+func (ga *GinAdapter) getCardPlays(c *gin.Context) {
+	cardPlays, err := ga.CardPlayUseCase.GetAll(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, cardPlays)
+}
+
+func (ga *GinAdapter) getInactiveCardPlays(c *gin.Context) {
+	cardPlays, err := ga.CardPlayUseCase.GetAllByInactive(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, cardPlays)
+}
+
+func (ga *GinAdapter) getCardPlay(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	cardPlay, err := ga.CardPlayUseCase.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"data": cardPlay})
+}
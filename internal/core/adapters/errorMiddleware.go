@@ -0,0 +1,71 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body.
+type Problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance,omitempty"`
+	RequestID string              `json:"request_id,omitempty"`
+	Errors    []domain.FieldError `json:"errors,omitempty"`
+}
+
+// ErrorHandler renders the last error a handler attached with c.Error as a
+// problem+json response. Handlers only decide what went wrong; the status
+// code and body shape are decided here.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		writeProblem(c, c.Errors.Last().Err)
+	}
+}
+
+// writeProblem renders err as the problem+json response of the request.
+func writeProblem(c *gin.Context, err error) {
+	problem := NewProblem(err)
+	problem.Instance = c.Request.URL.Path
+	problem.RequestID = c.GetString(RequestIDKey)
+
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+func NewProblem(err error) Problem {
+	var validationErrors domain.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrors):
+		return Problem{
+			Type:   "/problems/validation",
+			Title:  "Validation failed",
+			Status: http.StatusBadRequest,
+			Detail: "One or more fields are invalid",
+			Errors: validationErrors,
+		}
+	case errors.Is(err, domain.ErrNotFound):
+		return Problem{Type: "/problems/not-found", Title: "Not Found", Status: http.StatusNotFound, Detail: err.Error()}
+	case errors.Is(err, domain.ErrConflict):
+		return Problem{Type: "/problems/conflict", Title: "Conflict", Status: http.StatusConflict, Detail: err.Error()}
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return Problem{Type: "/problems/precondition-failed", Title: "Precondition Failed", Status: http.StatusPreconditionFailed, Detail: err.Error()}
+	case errors.Is(err, domain.ErrUnauthorized):
+		return Problem{Type: "/problems/unauthorized", Title: "Unauthorized", Status: http.StatusUnauthorized, Detail: err.Error()}
+	default:
+		return Problem{Type: "about:blank", Title: "Internal Server Error", Status: http.StatusInternalServerError}
+	}
+}
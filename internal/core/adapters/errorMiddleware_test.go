@@ -0,0 +1,150 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// problemRouter serves GET /fail, which fails with err or, when err is nil,
+// panics, behind the same error middlewares as SetupRouter.
+func problemRouter(err error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), Recovery(zap.NewNop()), ErrorHandler())
+	router.GET("/fail", func(c *gin.Context) {
+		if err == nil {
+			panic("lineup has no goalkeeper")
+		}
+		c.Error(err)
+	})
+	return router
+}
+
+func serveProblem(t *testing.T, router *gin.Engine) (*httptest.ResponseRecorder, Problem) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Type"); got != problemContentType {
+		t.Errorf("Content-Type = %q, want %q", got, problemContentType)
+	}
+	var problem Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("body %q is not a problem: %v", rec.Body.String(), err)
+	}
+	return rec, problem
+}
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Problem
+	}{
+		{
+			name: "not found",
+			err:  domain.NewNotFoundError("play %s not found", "42"),
+			want: Problem{Type: "/problems/not-found", Title: "Not Found", Status: http.StatusNotFound, Detail: "play 42 not found"},
+		},
+		{
+			name: "conflict",
+			err:  domain.NewConflictError("position already has attributes"),
+			want: Problem{Type: "/problems/conflict", Title: "Conflict", Status: http.StatusConflict, Detail: "position already has attributes"},
+		},
+		{
+			name: "validation with field errors",
+			err: domain.ValidationErrors{
+				{Field: "name", Message: "is required"},
+				{Field: "pac", Message: "must be between 1 and 99"},
+			},
+			want: Problem{
+				Type: "/problems/validation", Title: "Validation failed", Status: http.StatusBadRequest,
+				Detail: "One or more fields are invalid",
+				Errors: []domain.FieldError{{Field: "name", Message: "is required"}, {Field: "pac", Message: "must be between 1 and 99"}},
+			},
+		},
+		{
+			name: "wrapped field error",
+			err:  fmt.Errorf("creating match: %w", domain.NewFieldError("home", "must not be empty")),
+			want: Problem{
+				Type: "/problems/validation", Title: "Validation failed", Status: http.StatusBadRequest,
+				Detail: "One or more fields are invalid",
+				Errors: []domain.FieldError{{Field: "home", Message: "must not be empty"}},
+			},
+		},
+		{
+			name: "stale version",
+			err:  domain.NewPreconditionFailedError("play was modified: version 3 is not 2"),
+			want: Problem{Type: "/problems/precondition-failed", Title: "Precondition Failed", Status: http.StatusPreconditionFailed, Detail: "play was modified: version 3 is not 2"},
+		},
+		{
+			name: "unauthorized",
+			err:  domain.NewUnauthorizedError("missing bearer token"),
+			want: Problem{Type: "/problems/unauthorized", Title: "Unauthorized", Status: http.StatusUnauthorized, Detail: "missing bearer token"},
+		},
+		{
+			name: "unknown error hides its detail",
+			err:  errors.New("pq: password authentication failed"),
+			want: Problem{Type: "about:blank", Title: "Internal Server Error", Status: http.StatusInternalServerError},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewProblem(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewProblem = %+v, want %+v", got, tt.want)
+			}
+
+			rec, problem := serveProblem(t, problemRouter(tt.err))
+			want := tt.want
+			want.Instance, want.RequestID = "/fail", "req-1"
+			if rec.Code != tt.want.Status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want.Status)
+			}
+			if !reflect.DeepEqual(problem, want) {
+				t.Errorf("body = %+v, want %+v", problem, want)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerKeepsWrittenResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.GET("/fail", func(c *gin.Context) {
+		c.JSON(http.StatusAccepted, gin.H{"message": "started"})
+		c.Error(errors.New("late failure"))
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	if rec.Code != http.StatusAccepted || rec.Body.String() != `{"message":"started"}` {
+		t.Errorf("response = %d %s, want the handler's own 202", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	rec, problem := serveProblem(t, problemRouter(nil))
+	want := Problem{
+		Type: "about:blank", Title: "Internal Server Error", Status: http.StatusInternalServerError,
+		Instance: "/fail", RequestID: "req-1",
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+	if !reflect.DeepEqual(problem, want) {
+		t.Errorf("body = %+v, want %+v", problem, want)
+	}
+}
@@ -0,0 +1,75 @@
+package adapters
+
+import (
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) getModalityFormations(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	formations, err := ga.Formation.GetByModality(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, formations)
+}
+
+func (ga *GinAdapter) getFormation(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	formation, err := ga.Formation.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"data": formation})
+}
+
+func (ga *GinAdapter) createFormation(c *gin.Context) {
+	var formation domain.CreateFormationRequest
+	if !bindJSON(c, &formation) {
+		return
+	}
+	id, err := ga.Formation.Create(c.Request.Context(), formation)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(201, gin.H{"message": "Formation created successfully", "id": id})
+}
+
+func (ga *GinAdapter) deleteFormation(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ga.Formation.Delete(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Formation deleted successfully"})
+}
+
+func (ga *GinAdapter) buildLineup(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	var request domain.LineupRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+	draft, err := ga.Formation.Lineup(c.Request.Context(), id, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"data": draft})
+}
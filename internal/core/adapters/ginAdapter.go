@@ -1,9 +1,14 @@
 package adapters
 
 import (
+	"rachao/infra/metrics"
+	"rachao/infra/tracing"
+	"rachao/internal/core/domain"
 	"rachao/internal/core/usecase"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.uber.org/zap"
 )
 
 type GinAdapter struct {
@@ -16,6 +21,15 @@ type GinAdapter struct {
 	PositionUseCase *usecase.PositionUseCase
 	Attributes      *usecase.AttributesUseCase
 	Modality        *usecase.ModalityUseCase
+	Recalculate     *usecase.RecalculateUseCase
+	Season          *usecase.SeasonUseCase
+	Match           *usecase.MatchUseCase
+	Stats           *usecase.StatsUseCase
+	Rating          *usecase.RatingUseCase
+	Prediction      *usecase.PredictionUseCase
+	Formation       *usecase.FormationUseCase
+	AdminToken      string
+	Logger          *zap.Logger
 }
 
 func NewGinAdapter(
@@ -28,6 +42,15 @@ func NewGinAdapter(
 	positionUseCase *usecase.PositionUseCase,
 	attributes *usecase.AttributesUseCase,
 	modality *usecase.ModalityUseCase,
+	recalculate *usecase.RecalculateUseCase,
+	season *usecase.SeasonUseCase,
+	match *usecase.MatchUseCase,
+	stats *usecase.StatsUseCase,
+	rating *usecase.RatingUseCase,
+	prediction *usecase.PredictionUseCase,
+	formation *usecase.FormationUseCase,
+	adminToken string,
+	logger *zap.Logger,
 ) *GinAdapter {
 	return &GinAdapter{
 		HealthzUseCase:  healthzUseCase,
@@ -39,138 +62,134 @@ func NewGinAdapter(
 		PositionUseCase: positionUseCase,
 		Attributes:      attributes,
 		Modality:        modality,
+		Recalculate:     recalculate,
+		Season:          season,
+		Match:           match,
+		Stats:           stats,
+		Rating:          rating,
+		Prediction:      prediction,
+		Formation:       formation,
+		AdminToken:      adminToken,
+		Logger:          logger,
 	}
 }
 
 func (ga *GinAdapter) SetupRouter() *gin.Engine {
-	r := gin.Default()
+	r := gin.New()
+	r.Use(
+		otelgin.Middleware(tracing.ServiceName),
+		RequestID(),
+		RequestLogger(ga.Logger),
+		Recovery(ga.Logger),
+		Metrics(),
+		ErrorHandler(),
+	)
+	r.NoRoute(func(c *gin.Context) {
+		c.Error(domain.NewNotFoundError("route %s %s not found", c.Request.Method, c.Request.URL.Path))
+	})
 
-	r.GET("/healthz", ga.HealthzUseCase.Healthz())
+	r.GET("/healthz", ga.getLiveness)
+	r.GET("/healthz/live", ga.getLiveness)
+	r.GET("/healthz/ready", ga.getReadiness)
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	r.GET("/openapi.json", ga.getOpenAPI)
+	r.GET("/docs", ga.getDocs)
 
-	r.GET("/play", func(c *gin.Context) {
-		ga.PlayUseCase.GetAll(c.Request.Context(), c)
-	})
-	r.GET("/play/inactive", func(c *gin.Context) {
-		ga.PlayUseCase.GetAllByInactive(c.Request.Context(), c)
-	})
-	r.GET("/play/:id", func(c *gin.Context) {
-		ga.PlayUseCase.GetByID(c.Request.Context(), c)
-	})
-	r.POST("/play", func(c *gin.Context) {
-		ga.PlayUseCase.Create(c.Request.Context(), c)
-	})
-	r.PUT("/play/:id", func(c *gin.Context) {
-		ga.PlayUseCase.Update(c.Request.Context(), c)
-	})
-	r.DELETE("/play/:id", func(c *gin.Context) {
-		ga.PlayUseCase.Delete(c.Request.Context(), c)
-	})
-	r.GET("/play/name/:name", func(c *gin.Context) {
-		ga.PlayUseCase.GetByName(c.Request.Context(), c)
-	})
+	r.GET("/play", ga.getPlays)
+	r.GET("/play/inactive", ga.getInactivePlays)
+	r.GET("/play/:id", ga.getPlay)
+	r.POST("/play", ga.createPlay)
+	r.PUT("/play/:id", ga.updatePlay)
+	r.PATCH("/play/:id", ga.patchPlay)
+	r.DELETE("/play/:id", ga.deletePlay)
+	r.POST("/play/:id/activate", ga.activatePlay)
+	r.GET("/play/name/:name", ga.getPlayByName)
+	r.GET("/play/:id/stats", ga.getPlayStats)
+	r.GET("/play/:id/rating", ga.getPlayRating)
 
-	r.GET("/card/:id", func(c *gin.Context) {
-		ga.CardUseCase.GetByID(c.Request.Context(), c)
-	})
-	r.POST("/card/:id", func(c *gin.Context) {
-		ga.CardUseCase.Create(c.Request.Context(), c)
-	})
-	r.PUT("/card/:id", func(c *gin.Context) {
-		ga.CardUseCase.Update(c.Request.Context(), c)
-	})
+	r.GET("/card/:id", ga.getCard)
+	r.POST("/card/:id", ga.createCard)
+	r.PUT("/card/:id", ga.updateCard)
+	r.PATCH("/card/:id", ga.patchCard)
 
-	r.GET("/cardplay", func(c *gin.Context) {
-		ga.CardPlayUseCase.GetAll(c.Request.Context(), c)
-	})
-	r.GET("/cardplay/inactive", func(c *gin.Context) {
-		ga.CardPlayUseCase.GetAllByInactive(c.Request.Context(), c)
-	})
-	r.GET("/cardplay/:id", func(c *gin.Context) {
-		ga.CardPlayUseCase.GetByID(c.Request.Context(), c)
-	})
+	r.GET("/cardplay", ga.getCardPlays)
+	r.GET("/cardplay/inactive", ga.getInactiveCardPlays)
+	r.GET("/cardplay/:id", ga.getCardPlay)
 
-	r.GET("/nation", func(c *gin.Context) {
-		ga.NationUseCase.GetAll(c.Request.Context(), c)
-	})
-	r.GET("/nation/:id", func(c *gin.Context) {
-		ga.NationUseCase.GetByID(c.Request.Context(), c)
-	})
-	r.POST("/nation", func(c *gin.Context) {
-		ga.NationUseCase.Create(c.Request.Context(), c)
-	})
-	r.PUT("/nation/:id", func(c *gin.Context) {
-		ga.NationUseCase.Update(c.Request.Context(), c)
-	})
+	r.GET("/nation", ga.getNations)
+	r.GET("/nation/:id", ga.getNation)
+	r.POST("/nation", ga.createNation)
+	r.PUT("/nation/:id", ga.updateNation)
+	r.PATCH("/nation/:id", ga.patchNation)
 
-	r.GET("/photo/:id", func(c *gin.Context) {
-		ga.PhotoUseCase.GetByIDPlay(c.Request.Context(), c)
-	})
-	r.POST("/photo/:id", func(c *gin.Context) {
-		ga.PhotoUseCase.Create(c.Request.Context(), c)
-	})
-	r.PUT("/photo/:id", func(c *gin.Context) {
-		ga.PhotoUseCase.Update(c.Request.Context(), c)
-	})
-	r.DELETE("/photo/:id", func(c *gin.Context) {
-		ga.PhotoUseCase.Delete(c.Request.Context(), c)
-	})
+	r.GET("/photo/:id", ga.getPhoto)
+	r.POST("/photo/:id", ga.createPhoto)
+	r.PUT("/photo/:id", ga.updatePhoto)
+	r.DELETE("/photo/:id", ga.deletePhoto)
 
-	r.GET("/position", func(c *gin.Context) {
-		ga.PositionUseCase.GetAll(c.Request.Context(), c)
-	})
-	r.GET("/position/:id", func(c *gin.Context) {
-		ga.PositionUseCase.GetByID(c.Request.Context(), c)
-	})
-	r.POST("/position", func(c *gin.Context) {
-		ga.PositionUseCase.Create(c.Request.Context(), c)
-	})
-	r.PUT("/position/:id", func(c *gin.Context) {
-		ga.PositionUseCase.Update(c.Request.Context(), c)
-	})
-	r.DELETE("/position/:id", func(c *gin.Context) {
-		ga.PositionUseCase.Delete(c.Request.Context(), c)
-	})
+	r.GET("/position", ga.getPositions)
+	r.GET("/position/:id", ga.getPosition)
+	r.POST("/position", ga.createPosition)
+	r.PUT("/position/:id", ga.updatePosition)
+	r.PATCH("/position/:id", ga.patchPosition)
+	r.DELETE("/position/:id", ga.deletePosition)
 
-	r.POST("/attributes", func(c *gin.Context) {
-		ga.Attributes.Create(c.Request.Context(), c)
-	})
-	r.GET("/attributes", func(c *gin.Context) {
-		ga.Attributes.GetAll(c.Request.Context(), c)
-	})
-	r.GET("/attributes/:id", func(c *gin.Context) {
-		ga.Attributes.GetByIDAttributes(c.Request.Context(), c)
-	})
-	r.GET("/attributes/position/:id", func(c *gin.Context) {
-		ga.Attributes.GetByIDPosition(c.Request.Context(), c)
-	})
-	r.PUT("/attributes/:id", func(c *gin.Context) {
-		ga.Attributes.Update(c.Request.Context(), c)
-	})
-	r.DELETE("/attributes/:id", func(c *gin.Context) {
-		ga.Attributes.Delete(c.Request.Context(), c)
-	})
+	r.POST("/attributes", ga.createAttributes)
+	r.GET("/attributes", ga.getAllAttributes)
+	r.GET("/attributes/:id", ga.getAttributes)
+	r.GET("/attributes/position/:id", ga.getAttributesByPosition)
+	r.PUT("/attributes/:id", ga.updateAttributes)
+	r.PATCH("/attributes/:id", ga.patchAttributes)
+	r.DELETE("/attributes/:id", ga.deleteAttributes)
 
-	r.GET("/modality", func(c *gin.Context) {
-		ga.Modality.GetAll(c.Request.Context(), c)
-	})
-	r.GET("/modality/inactive", func(c *gin.Context) {
-		ga.Modality.GetAllByInactive(c.Request.Context(), c)
-	})
-	r.GET("/modality/:id", func(c *gin.Context) {
-		ga.Modality.GetByID(c.Request.Context(), c)
-	})
-	r.POST("/modality", func(c *gin.Context) {
-		ga.Modality.Create(c.Request.Context(), c)
-	})
-	r.PUT("/modality/:id", func(c *gin.Context) {
-		ga.Modality.Update(c.Request.Context(), c)
-	})
-	r.DELETE("/modality/:id", func(c *gin.Context) {
-		ga.Modality.Inactive(c.Request.Context(), c)
-	})
-	r.POST("/modality/activate/:id", func(c *gin.Context) {
-		ga.Modality.Active(c.Request.Context(), c)
-	})
+	r.GET("/modality", ga.getModalities)
+	r.GET("/modality/inactive", ga.getInactiveModalities)
+	r.GET("/modality/:id", ga.getModality)
+	r.POST("/modality", ga.createModality)
+	r.PUT("/modality/:id", ga.updateModality)
+	r.PATCH("/modality/:id", ga.patchModality)
+	r.DELETE("/modality/:id", ga.inactivateModality)
+	r.POST("/modality/activate/:id", ga.activateModality)
+	r.GET("/modality/:id/formation", ga.getModalityFormations)
+
+	r.GET("/formation/:id", ga.getFormation)
+	r.POST("/formation", ga.createFormation)
+	r.DELETE("/formation/:id", ga.deleteFormation)
+	r.POST("/formation/:id/lineup", ga.buildLineup)
+
+	r.POST("/overall/recalculate", ga.recalculateOveralls)
+	r.GET("/overall/recalculate/:id", ga.getRecalculateJob)
+
+	r.GET("/season", ga.getSeasons)
+	r.GET("/season/:id", ga.getSeason)
+	r.POST("/season", ga.createSeason)
+	r.PUT("/season/:id", ga.updateSeason)
+	r.POST("/season/:id/close", ga.closeSeason)
+	r.GET("/season/:id/snapshots", ga.getSeasonSnapshots)
+	r.GET("/season/:id/stats", ga.getSeasonStats)
+	r.GET("/season/:id/matches", ga.getSeasonMatches)
+
+	r.GET("/match/:id", ga.getMatch)
+	r.POST("/match", ga.createMatch)
+	r.PUT("/match/:id/result", ga.recordMatchResult)
+	r.GET("/match/:id/prediction", ga.getMatchPrediction)
+	r.DELETE("/match/:id", ga.deleteMatch)
+
+	r.GET("/stats/leaderboard", ga.getLeaderboard)
+
+	r.GET("/rating", ga.getRatings)
+
+	admin := r.Group("/admin", AdminOnly(ga.AdminToken))
+	admin.DELETE("/play/:id", ga.purgePlay)
+	admin.POST("/rating/rebuild", ga.rebuildRatings)
+
+	missing, err := UndocumentedRoutes(r.Routes())
+	if err != nil {
+		ga.Logger.Error("Invalid OpenAPI document", zap.Error(err))
+	}
+	for _, route := range missing {
+		ga.Logger.Warn("Route missing from the OpenAPI document", zap.String("route", route))
+	}
 
 	return r
 }
@@ -0,0 +1,64 @@
+package adapters
+
+import (
+	"errors"
+	"rachao/internal/core/domain"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// The helpers below attach a validation error to the context and report
+// false when the input is unusable, so handlers can simply return.
+
+func uuidParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.Error(domain.NewFieldError(name, "invalid ID format"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func intParam(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		c.Error(domain.NewFieldError(name, "invalid ID format"))
+		return 0, false
+	}
+	return id, true
+}
+
+func bindJSON(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		c.Error(bindingError(err))
+		return false
+	}
+	return true
+}
+
+// bindQuery is bindJSON for query string parameters. Values that do not
+// parse, such as letters in a number, have no field to point at and are
+// reported against the query as a whole.
+func bindQuery(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindQuery(obj); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			c.Error(bindingError(err))
+		} else {
+			c.Error(domain.NewFieldError("query", "invalid query parameters: %v", err))
+		}
+		return false
+	}
+	return true
+}
+
+func respondList[T any](c *gin.Context, items []T) {
+	if items == nil {
+		c.JSON(200, gin.H{"message": "No data found"})
+		return
+	}
+	c.JSON(200, gin.H{"data": items})
+}
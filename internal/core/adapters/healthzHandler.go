@@ -0,0 +1,20 @@
+package adapters
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) getLiveness(c *gin.Context) {
+	c.JSON(http.StatusOK, ga.HealthzUseCase.Live(c.Request.Context()))
+}
+
+func (ga *GinAdapter) getReadiness(c *gin.Context) {
+	healthz, ready := ga.HealthzUseCase.Ready(c.Request.Context())
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, healthz)
+		return
+	}
+	c.JSON(http.StatusOK, healthz)
+}
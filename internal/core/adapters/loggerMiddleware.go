@@ -0,0 +1,66 @@
+package adapters
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"rachao/infra/logging"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RequestLogger attaches a logger carrying the request and trace IDs to the
+// request context, then logs one structured line per request. It must run
+// after RequestID.
+func RequestLogger(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		ctx := c.Request.Context()
+		requestLogger := logger.With(logging.Fields(ctx)...)
+		c.Request = c.Request.WithContext(logging.WithLogger(ctx, requestLogger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := zapcore.InfoLevel
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = zapcore.ErrorLevel
+		case status >= http.StatusBadRequest:
+			level = zapcore.WarnLevel
+		}
+
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("route", c.FullPath()),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", c.ClientIP()),
+			zap.Int("size", c.Writer.Size()),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.Strings("errors", c.Errors.Errors()))
+		}
+		requestLogger.Log(level, "HTTP request", fields...)
+	}
+}
+
+// Recovery turns a panic into a problem+json 500 and logs it with the
+// request fields instead of Gin's plain text dump. The panic unwinds past
+// ErrorHandler, so the problem is written here, unless the handler had
+// already started the response.
+func Recovery(logger *zap.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		logging.FromContext(c.Request.Context(), logger).Error("Panic handling request",
+			zap.Any("panic", recovered), zap.Stack("stack"))
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+		writeProblem(c, fmt.Errorf("panic: %v", recovered))
+	})
+}
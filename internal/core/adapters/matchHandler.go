@@ -0,0 +1,73 @@
+package adapters
+
+import (
+	"context"
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) getMatch(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	match, err := ga.Match.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	setETag(c, match.Version)
+	c.JSON(200, gin.H{"data": match})
+}
+
+func (ga *GinAdapter) createMatch(c *gin.Context) {
+	var match domain.CreateMatchRequest
+	if !bindJSON(c, &match) {
+		return
+	}
+	id, err := ga.Match.Create(c.Request.Context(), match)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(201, gin.H{"message": "Match created successfully", "id": id})
+}
+
+func (ga *GinAdapter) recordMatchResult(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	write := func(ctx context.Context, result domain.MatchResultRequest, version int) (int, error) {
+		return ga.Match.RecordResult(ctx, id, result, version)
+	}
+	if replaceVersioned(c, write) {
+		c.JSON(200, gin.H{"message": "Match result recorded successfully"})
+	}
+}
+
+func (ga *GinAdapter) deleteMatch(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ga.Match.Delete(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Match deleted successfully"})
+}
+
+func (ga *GinAdapter) getMatchPrediction(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	prediction, err := ga.Prediction.Predict(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"data": prediction})
+}
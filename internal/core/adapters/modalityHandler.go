@@ -0,0 +1,109 @@
+package adapters
+
+import (
+	"context"
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) getModalities(c *gin.Context) {
+	modalities, err := ga.Modality.GetAll(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, modalities)
+}
+
+func (ga *GinAdapter) getInactiveModalities(c *gin.Context) {
+	modalities, err := ga.Modality.GetAllByInactive(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, modalities)
+}
+
+func (ga *GinAdapter) getModality(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	modality, err := ga.Modality.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	setETag(c, modality.Version)
+	c.JSON(200, gin.H{"data": modality})
+}
+
+func (ga *GinAdapter) createModality(c *gin.Context) {
+	var modality domain.CreateModalityRequest
+	if !bindJSON(c, &modality) {
+		return
+	}
+	id, err := ga.Modality.Create(c.Request.Context(), modality)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(201, gin.H{"message": "Modality created successfully", "id": id})
+}
+
+func (ga *GinAdapter) updateModality(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	write := func(ctx context.Context, modality domain.Modality, version int) (int, error) {
+		return ga.Modality.Update(ctx, id, modality, version)
+	}
+	if replaceVersioned(c, write) {
+		c.JSON(200, gin.H{"message": "Modality updated successfully"})
+	}
+}
+
+// patchModality changes the name or the number of players of a modality;
+// the same checks as a full update apply to the merged result.
+func (ga *GinAdapter) patchModality(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	current := func(ctx context.Context) (domain.Modality, int, error) {
+		modality, err := ga.Modality.GetByID(ctx, id)
+		return modality, modality.Version, err
+	}
+	write := func(ctx context.Context, modality domain.Modality, version int) (int, error) {
+		return ga.Modality.Update(ctx, id, modality, version)
+	}
+	if patchVersioned(c, current, write) {
+		c.JSON(200, gin.H{"message": "Modality updated successfully"})
+	}
+}
+
+func (ga *GinAdapter) inactivateModality(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ga.Modality.Inactive(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Modality inactivated successfully"})
+}
+
+func (ga *GinAdapter) activateModality(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ga.Modality.Active(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Modality activated successfully"})
+}
@@ -0,0 +1,76 @@
+package adapters
+
+import (
+	"context"
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) getNations(c *gin.Context) {
+	nations, err := ga.NationUseCase.GetAll(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, nations)
+}
+
+func (ga *GinAdapter) getNation(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	nation, err := ga.NationUseCase.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	setETag(c, nation.Version)
+	c.JSON(200, gin.H{"data": nation})
+}
+
+func (ga *GinAdapter) createNation(c *gin.Context) {
+	var nation domain.CreateNationRequest
+	if !bindJSON(c, &nation) {
+		return
+	}
+	id, err := ga.NationUseCase.Create(c.Request.Context(), nation)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(201, gin.H{"message": "Nation created successfully", "id": id})
+}
+
+func (ga *GinAdapter) updateNation(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	write := func(ctx context.Context, nation domain.Nation, version int) (int, error) {
+		return ga.NationUseCase.Update(ctx, id, nation, version)
+	}
+	if replaceVersioned(c, write) {
+		c.JSON(200, gin.H{"message": "Nation updated successfully"})
+	}
+}
+
+// patchNation renames a nation or changes its acronym without resending the
+// other.
+func (ga *GinAdapter) patchNation(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	current := func(ctx context.Context) (domain.Nation, int, error) {
+		nation, err := ga.NationUseCase.GetByID(ctx, id)
+		return nation, nation.Version, err
+	}
+	write := func(ctx context.Context, nation domain.Nation, version int) (int, error) {
+		return ga.NationUseCase.Update(ctx, id, nation, version)
+	}
+	if patchVersioned(c, current, write) {
+		c.JSON(200, gin.H{"message": "Nation updated successfully"})
+	}
+}
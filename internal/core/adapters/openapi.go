@@ -0,0 +1,62 @@
+package adapters
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openAPISpec documents every route registered in SetupRouter. Keep it in
+// sync when adding a route: TestEveryRouteIsDocumented fails on the routes it
+// is missing, and SetupRouter also logs them at startup.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Rachão API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });</script>
+</body>
+</html>`
+
+func (ga *GinAdapter) getOpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", openAPISpec)
+}
+
+func (ga *GinAdapter) getDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
+
+var ginParam = regexp.MustCompile(`:(\w+)`)
+
+// UndocumentedRoutes lists the routes, as "METHOD /path", that have no
+// operation in the OpenAPI document.
+func UndocumentedRoutes(routes gin.RoutesInfo) ([]string, error) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, route := range routes {
+		path := ginParam.ReplaceAllString(route.Path, "{$1}")
+		if _, ok := spec.Paths[path][strings.ToLower(route.Method)]; !ok {
+			missing = append(missing, route.Method+" "+route.Path)
+		}
+	}
+	return missing, nil
+}
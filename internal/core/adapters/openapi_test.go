@@ -0,0 +1,45 @@
+package adapters
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestOpenAPISpecParses(t *testing.T) {
+	var spec struct {
+		OpenAPI    string                     `json:"openapi"`
+		Paths      map[string]json.RawMessage `json:"paths"`
+		Components map[string]map[string]any  `json:"components"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("openapi.json does not parse: %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") || len(spec.Paths) == 0 {
+		t.Fatalf("openapi.json is not an OpenAPI 3 document with paths")
+	}
+
+	// Every local $ref must point at a declared component.
+	for _, match := range regexp.MustCompile(`"\$ref":\s*"#/components/(\w+)/(\w+)"`).FindAllStringSubmatch(string(openAPISpec), -1) {
+		if _, ok := spec.Components[match[1]][match[2]]; !ok {
+			t.Errorf("$ref to missing component %s/%s", match[1], match[2])
+		}
+	}
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ga := NewGinAdapter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, "", zap.NewNop())
+
+	missing, err := UndocumentedRoutes(ga.SetupRouter().Routes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, route := range missing {
+		t.Errorf("%s is missing from openapi.json", route)
+	}
+}
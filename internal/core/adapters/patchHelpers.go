@@ -0,0 +1,188 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"rachao/internal/core/domain"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	ifMatchHeader  = "If-Match"
+	mergePatchType = "application/merge-patch+json"
+)
+
+// setETag exposes the version of the record being returned or written, to
+// be sent back in If-Match by the next write.
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatch returns the version required by the If-Match header, or 0 when the
+// header is missing or "*" and any version may be overwritten.
+func ifMatch(c *gin.Context) (int, bool) {
+	value := strings.TrimSpace(c.GetHeader(ifMatchHeader))
+	if value == "" || value == "*" {
+		return 0, true
+	}
+	unquoted, err := strconv.Unquote(strings.TrimPrefix(value, "W/"))
+	if err == nil {
+		var version int
+		version, err = strconv.Atoi(unquoted)
+		if err == nil && version > 0 {
+			return version, true
+		}
+	}
+	c.Error(domain.NewFieldError(ifMatchHeader, "must be an ETag returned by this API"))
+	return 0, false
+}
+
+// versionedWrite stores body over a record if it is still at version (0
+// skips the check) and returns the version it ends at.
+type versionedWrite[T any] func(ctx context.Context, body T, version int) (int, error)
+
+// replaceVersioned answers a PUT: it binds the full body and writes it at the
+// version required by If-Match. It reports whether the write succeeded and
+// the new ETag was set; otherwise the error is already on c.
+func replaceVersioned[T any](c *gin.Context, write versionedWrite[T]) bool {
+	version, ok := ifMatch(c)
+	if !ok {
+		return false
+	}
+	var body T
+	if !bindJSON(c, &body) {
+		return false
+	}
+	return writeVersioned(c, write, body, version)
+}
+
+// patchVersioned answers a PATCH: it merges the body into the record read by
+// current and writes the result. Without If-Match the version current read
+// is required instead, so a change made between the read and the write is
+// rejected rather than silently lost.
+func patchVersioned[T any](c *gin.Context, current func(ctx context.Context) (T, int, error), write versionedWrite[T]) bool {
+	version, ok := ifMatch(c)
+	if !ok {
+		return false
+	}
+	record, readVersion, err := current(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return false
+	}
+	if version == 0 {
+		version = readVersion
+	}
+	body, ok := mergePatch(c, record)
+	if !ok {
+		return false
+	}
+	return writeVersioned(c, write, body, version)
+}
+
+func writeVersioned[T any](c *gin.Context, write versionedWrite[T], body T, version int) bool {
+	newVersion, err := write(c.Request.Context(), body, version)
+	if err != nil {
+		c.Error(err)
+		return false
+	}
+	setETag(c, newVersion)
+	return true
+}
+
+// mergePatch applies the JSON Merge Patch (RFC 7396) in the request body to
+// current and validates the result as if it had been sent in full. Only the
+// members present in the patch change; null resets a member to its zero
+// value.
+func mergePatch[T any](c *gin.Context, current T) (T, bool) {
+	var zero T
+	contentType := c.ContentType()
+	if contentType != mergePatchType && contentType != binding.MIMEJSON {
+		c.Error(domain.NewFieldError("Content-Type", "must be %s", mergePatchType))
+		return zero, false
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.Error(domain.NewFieldError("body", "invalid request body"))
+		return zero, false
+	}
+	var patch map[string]any
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		c.Error(domain.NewFieldError("body", "must be a JSON object"))
+		return zero, false
+	}
+
+	document, err := toDocument(current)
+	if err != nil {
+		c.Error(err)
+		return zero, false
+	}
+	merged, err := json.Marshal(applyMergePatch(document, patch))
+	if err != nil {
+		c.Error(err)
+		return zero, false
+	}
+
+	var result T
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		if field, ok := unknownField(err); ok {
+			c.Error(domain.NewFieldError(field, "is not a known field"))
+			return zero, false
+		}
+		c.Error(bindingError(err))
+		return zero, false
+	}
+	if err := binding.Validator.ValidateStruct(&result); err != nil {
+		c.Error(bindingError(err))
+		return zero, false
+	}
+	return result, true
+}
+
+func toDocument(value any) (map[string]any, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]any
+	err = json.Unmarshal(raw, &document)
+	return document, err
+}
+
+// applyMergePatch implements the MergePatch function of RFC 7396.
+func applyMergePatch(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = applyMergePatch(targetObject[name], value)
+	}
+	return targetObject
+}
+
+func unknownField(err error) (string, bool) {
+	var field string
+	if _, scanErr := fmt.Sscanf(err.Error(), "json: unknown field %q", &field); scanErr != nil {
+		return "", false
+	}
+	return field, true
+}
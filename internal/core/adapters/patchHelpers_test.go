@@ -0,0 +1,172 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestApplyMergePatch runs the examples of RFC 7396, Appendix A.
+func TestApplyMergePatch(t *testing.T) {
+	tests := []struct{ target, patch, want string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		var target, patch, want any
+		for _, doc := range []struct {
+			raw string
+			to  *any
+		}{{tt.target, &target}, {tt.patch, &patch}, {tt.want, &want}} {
+			if err := json.Unmarshal([]byte(doc.raw), doc.to); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := applyMergePatch(target, patch); !reflect.DeepEqual(got, want) {
+			t.Errorf("merge %s into %s = %v, want %s", tt.patch, tt.target, got, tt.want)
+		}
+	}
+}
+
+type patchRecord struct {
+	Name   string `json:"name" binding:"required"`
+	Amount int    `json:"amount"`
+	Nested struct {
+		A int    `json:"a"`
+		B string `json:"b"`
+	} `json:"nested"`
+}
+
+// patchRouter serves PATCH / over a record at version 3, echoing what it
+// would write. The write fails like a repository would when the version is
+// stale.
+func patchRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler())
+
+	current := patchRecord{Name: "Society", Amount: 7}
+	current.Nested.A, current.Nested.B = 1, "b"
+	router.PATCH("/", func(c *gin.Context) {
+		var written patchRecord
+		read := func(ctx context.Context) (patchRecord, int, error) { return current, 3, nil }
+		write := func(ctx context.Context, body patchRecord, version int) (int, error) {
+			if version != 3 {
+				return 0, domain.NewPreconditionFailedError("record is at version 3, not %d", version)
+			}
+			written = body
+			return version + 1, nil
+		}
+		if patchVersioned(c, read, write) {
+			c.JSON(http.StatusOK, written)
+		}
+	})
+	return router
+}
+
+func patchRequest(router *gin.Engine, contentType, ifMatch, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	if ifMatch != "" {
+		req.Header.Set(ifMatchHeader, ifMatch)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMergePatch(t *testing.T) {
+	router := patchRouter()
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		want        string
+	}{
+		{"nested member", mergePatchType, `{"nested":{"b":"x"}}`, 200, `{"name":"Society","amount":7,"nested":{"a":1,"b":"x"}}`},
+		{"null resets a member", mergePatchType, `{"amount":null,"nested":{"a":null}}`, 200, `{"name":"Society","amount":0,"nested":{"a":0,"b":"b"}}`},
+		{"null replaces an object", mergePatchType, `{"nested":null}`, 200, `{"name":"Society","amount":7,"nested":{"a":0,"b":""}}`},
+		{"plain json", "application/json", `{"amount":5}`, 200, `{"name":"Society","amount":5,"nested":{"a":1,"b":"b"}}`},
+		{"empty patch", mergePatchType, `{}`, 200, `{"name":"Society","amount":7,"nested":{"a":1,"b":"b"}}`},
+		{"merged result is validated", mergePatchType, `{"name":null}`, 400, ""},
+		{"wrong type", mergePatchType, `{"amount":"five"}`, 400, ""},
+		{"unknown member", mergePatchType, `{"players":5}`, 400, ""},
+		{"array patch", mergePatchType, `[{"amount":5}]`, 400, ""},
+		{"null patch", mergePatchType, `null`, 400, ""},
+		{"string patch", mergePatchType, `"Society"`, 400, ""},
+		{"not json", mergePatchType, `amount=5`, 400, ""},
+		{"other content type", "application/json-patch+json", `[]`, 400, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := patchRequest(router, tt.contentType, "", tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status != 200 {
+				if contentType := rec.Header().Get("Content-Type"); contentType != problemContentType {
+					t.Errorf("Content-Type = %q, want %q", contentType, problemContentType)
+				}
+				return
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != tt.want {
+				t.Errorf("merged = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	router := patchRouter()
+	tests := []struct {
+		name    string
+		ifMatch string
+		status  int
+	}{
+		{"missing uses the version read", "", 200},
+		{"any version", "*", 200},
+		{"strong", `"3"`, 200},
+		{"weak", `W/"3"`, 200},
+		{"padded", ` "3" `, 200},
+		{"stale strong", `"2"`, 412},
+		{"stale weak", `W/"2"`, 412},
+		{"unquoted", `3`, 400},
+		{"not a version", `"abc"`, 400},
+		{"zero", `"0"`, 400},
+		{"negative", `"-1"`, 400},
+		{"list", `"2", "3"`, 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := patchRequest(router, mergePatchType, tt.ifMatch, `{"amount":5}`)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status == 200 && rec.Header().Get("ETag") != `"4"` {
+				t.Errorf("ETag = %q, want %q", rec.Header().Get("ETag"), `"4"`)
+			}
+		})
+	}
+}
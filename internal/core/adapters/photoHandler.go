@@ -0,0 +1,87 @@
+package adapters
+
+import (
+	"io"
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) getPhoto(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	photo, err := ga.PhotoUseCase.GetByIDPlay(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Data(200, "image/jpeg", photo.Photo)
+}
+
+func (ga *GinAdapter) createPhoto(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	photo, ok := readPhoto(c)
+	if !ok {
+		return
+	}
+	idPhoto, err := ga.PhotoUseCase.Create(c.Request.Context(), id, photo)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"data": idPhoto})
+}
+
+func (ga *GinAdapter) updatePhoto(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	photo, ok := readPhoto(c)
+	if !ok {
+		return
+	}
+	if err := ga.PhotoUseCase.Update(c.Request.Context(), id, photo); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Photo updated successfully"})
+}
+
+func (ga *GinAdapter) deletePhoto(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ga.PhotoUseCase.Delete(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Photo deleted successfully"})
+}
+
+// readPhoto reads the "photo" file of a multipart form.
+func readPhoto(c *gin.Context) ([]byte, bool) {
+	header, err := c.FormFile("photo")
+	if err != nil {
+		c.Error(domain.NewFieldError("photo", "error getting photo from form"))
+		return nil, false
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.Error(domain.NewFieldError("photo", "error opening photo file"))
+		return nil, false
+	}
+	defer file.Close()
+	photo, err := io.ReadAll(file)
+	if err != nil {
+		c.Error(domain.NewFieldError("photo", "error reading photo file"))
+		return nil, false
+	}
+	return photo, true
+}
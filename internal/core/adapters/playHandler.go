@@ -0,0 +1,135 @@
+package adapters
+
+import (
+	"context"
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) getPlays(c *gin.Context) {
+	plays, err := ga.PlayUseCase.GetAll(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, plays)
+}
+
+func (ga *GinAdapter) getInactivePlays(c *gin.Context) {
+	plays, err := ga.PlayUseCase.GetAllByInactive(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, plays)
+}
+
+func (ga *GinAdapter) getPlay(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	play, err := ga.PlayUseCase.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	setETag(c, play.Version)
+	c.JSON(200, gin.H{"data": play})
+}
+
+func (ga *GinAdapter) createPlay(c *gin.Context) {
+	var play domain.CreatePlayRequest
+	if !bindJSON(c, &play) {
+		return
+	}
+	id, err := ga.PlayUseCase.Create(c.Request.Context(), play)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(201, gin.H{"message": "Play created successfully", "id": id})
+}
+
+func (ga *GinAdapter) updatePlay(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	write := func(ctx context.Context, play domain.Play, version int) (int, error) {
+		return ga.PlayUseCase.Update(ctx, id, play, version)
+	}
+	if replaceVersioned(c, write) {
+		c.JSON(200, gin.H{"message": "Play updated successfully"})
+	}
+}
+
+// patchPlay moves a play to another position or nation, or renames it,
+// keeping the fields left out of the body as stored.
+func (ga *GinAdapter) patchPlay(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	current := func(ctx context.Context) (domain.Play, int, error) {
+		play, err := ga.PlayUseCase.GetByID(ctx, id)
+		return play, play.Version, err
+	}
+	write := func(ctx context.Context, play domain.Play, version int) (int, error) {
+		return ga.PlayUseCase.Update(ctx, id, play, version)
+	}
+	if patchVersioned(c, current, write) {
+		c.JSON(200, gin.H{"message": "Play updated successfully"})
+	}
+}
+
+func (ga *GinAdapter) deletePlay(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ga.PlayUseCase.Delete(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Play deleted successfully"})
+}
+
+func (ga *GinAdapter) activatePlay(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ga.PlayUseCase.Activate(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Play activated successfully"})
+}
+
+// purgePlay permanently deletes a play and every row that references it,
+// including its lines in past matches, and rebuilds the ratings without it.
+// ratings_rebuilt is false when the rebuild failed and should be retried
+// with POST /admin/rating/rebuild.
+func (ga *GinAdapter) purgePlay(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	rebuilt, err := ga.PlayUseCase.Purge(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Play purged successfully", "ratings_rebuilt": rebuilt})
+}
+
+func (ga *GinAdapter) getPlayByName(c *gin.Context) {
+	play, err := ga.PlayUseCase.GetByName(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"data": play})
+}
@@ -0,0 +1,88 @@
+package adapters
+
+import (
+	"context"
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) getPositions(c *gin.Context) {
+	positions, err := ga.PositionUseCase.GetAll(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, positions)
+}
+
+func (ga *GinAdapter) getPosition(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	position, err := ga.PositionUseCase.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	setETag(c, position.Version)
+	c.JSON(200, gin.H{"data": position})
+}
+
+func (ga *GinAdapter) createPosition(c *gin.Context) {
+	var position domain.CreatePositionRequest
+	if !bindJSON(c, &position) {
+		return
+	}
+	id, err := ga.PositionUseCase.Create(c.Request.Context(), position)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(201, gin.H{"message": "Position created successfully", "id": id})
+}
+
+func (ga *GinAdapter) updatePosition(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	write := func(ctx context.Context, position domain.Position, version int) (int, error) {
+		return ga.PositionUseCase.Update(ctx, id, position, version)
+	}
+	if replaceVersioned(c, write) {
+		c.JSON(200, gin.H{"message": "Position updated successfully"})
+	}
+}
+
+// patchPosition renames a position or changes its acronym without resending
+// the other.
+func (ga *GinAdapter) patchPosition(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	current := func(ctx context.Context) (domain.Position, int, error) {
+		position, err := ga.PositionUseCase.GetByID(ctx, id)
+		return position, position.Version, err
+	}
+	write := func(ctx context.Context, position domain.Position, version int) (int, error) {
+		return ga.PositionUseCase.Update(ctx, id, position, version)
+	}
+	if patchVersioned(c, current, write) {
+		c.JSON(200, gin.H{"message": "Position updated successfully"})
+	}
+}
+
+func (ga *GinAdapter) deletePosition(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ga.PositionUseCase.Delete(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Position deleted successfully"})
+}
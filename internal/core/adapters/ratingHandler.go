@@ -0,0 +1,36 @@
+package adapters
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) getRatings(c *gin.Context) {
+	ratings, err := ga.Rating.GetAll(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, ratings)
+}
+
+func (ga *GinAdapter) getPlayRating(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	history, err := ga.Rating.GetHistory(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, history)
+}
+
+func (ga *GinAdapter) rebuildRatings(c *gin.Context) {
+	count, err := ga.Rating.Rebuild(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Ratings rebuilt successfully", "changes": count})
+}
@@ -0,0 +1,37 @@
+package adapters
+
+import (
+	"rachao/infra/logging"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	RequestIDKey    = "request_id"
+)
+
+// validRequestID bounds what a client may choose as its request ID. It is
+// written to every log line and sent as the AMQP correlation ID, a short
+// string that cannot hold more than 255 bytes.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID reuses the caller's X-Request-ID when it is up to 128 letters,
+// digits, dots, underscores or dashes, and assigns a new one otherwise. It
+// echoes the ID back so clients can quote it when reporting a problem, and
+// stores it in the request context for the use cases and the messages they
+// publish.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set(RequestIDKey, requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
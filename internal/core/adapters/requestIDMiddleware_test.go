@@ -0,0 +1,54 @@
+package adapters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := []struct {
+		name   string
+		header string
+		kept   bool
+	}{
+		{"uuid", "3f2b8c1e-0d4a-4c5e-9a7b-1c2d3e4f5a6b", true},
+		{"token", "web.checkout_42", true},
+		{"max length", strings.Repeat("a", 128), true},
+		{"missing", "", false},
+		{"too long", strings.Repeat("a", 129), false},
+		{"space", "abc def", false},
+		{"control characters", "abc\r\nX-Injected: 1", false},
+		{"non ascii", "pedido-ção", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(RequestIDHeader)
+			if tt.kept {
+				if got != tt.header {
+					t.Fatalf("X-Request-ID = %q, want %q", got, tt.header)
+				}
+				return
+			}
+			if _, err := uuid.Parse(got); err != nil {
+				t.Fatalf("X-Request-ID = %q, want a new UUID", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package adapters
+
+import (
+	"context"
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) getSeasons(c *gin.Context) {
+	seasons, err := ga.Season.GetAll(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, seasons)
+}
+
+func (ga *GinAdapter) getSeason(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	season, err := ga.Season.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	setETag(c, season.Version)
+	c.JSON(200, gin.H{"data": season})
+}
+
+func (ga *GinAdapter) createSeason(c *gin.Context) {
+	var season domain.CreateSeasonRequest
+	if !bindJSON(c, &season) {
+		return
+	}
+	id, err := ga.Season.Create(c.Request.Context(), season)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(201, gin.H{"message": "Season created successfully", "id": id})
+}
+
+func (ga *GinAdapter) updateSeason(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	write := func(ctx context.Context, season domain.Season, version int) (int, error) {
+		return ga.Season.Update(ctx, id, season, version)
+	}
+	if replaceVersioned(c, write) {
+		c.JSON(200, gin.H{"message": "Season updated successfully"})
+	}
+}
+
+func (ga *GinAdapter) closeSeason(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	newVersion, err := ga.Season.Close(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	setETag(c, newVersion)
+	c.JSON(200, gin.H{"message": "Season closed successfully"})
+}
+
+func (ga *GinAdapter) getSeasonSnapshots(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	snapshots, err := ga.Season.GetSnapshots(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, snapshots)
+}
+
+func (ga *GinAdapter) getSeasonStats(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	stats, err := ga.Season.GetStats(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, stats)
+}
+
+func (ga *GinAdapter) getSeasonMatches(c *gin.Context) {
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	matches, err := ga.Season.GetMatches(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, matches)
+}
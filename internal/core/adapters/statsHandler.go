@@ -0,0 +1,37 @@
+package adapters
+
+import (
+	"rachao/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ga *GinAdapter) getLeaderboard(c *gin.Context) {
+	var query domain.LeaderboardQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+	entries, err := ga.Stats.Leaderboard(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondList(c, entries)
+}
+
+func (ga *GinAdapter) getPlayStats(c *gin.Context) {
+	id, ok := uuidParam(c, "id")
+	if !ok {
+		return
+	}
+	var query domain.PlayerStatsQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+	stats, err := ga.Stats.PlayerStats(c.Request.Context(), id, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"data": stats})
+}
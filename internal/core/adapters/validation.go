@@ -0,0 +1,104 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"rachao/internal/core/domain"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// init makes the binding validator report fields by their JSON name and
+// adds the rules the request types use beyond the built-in ones.
+func init() {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	engine.RegisterValidation("notblank", func(fl validator.FieldLevel) bool {
+		return strings.TrimSpace(fl.Field().String()) != ""
+	})
+}
+
+// bindingError turns what ShouldBindJSON returned into field errors the
+// client can act on.
+func bindingError(err error) error {
+	var validationErrors validator.ValidationErrors
+	var typeError *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &validationErrors):
+		var errs domain.ValidationErrors
+		for _, fieldError := range validationErrors {
+			errs = append(errs, domain.FieldError{
+				Field:   fieldError.Field(),
+				Message: ruleMessage(fieldError),
+			})
+		}
+		return errs
+	case errors.As(err, &typeError):
+		return domain.NewFieldError(typeError.Field, "must be %s", typeName(typeError.Type.Kind()))
+	default:
+		return domain.NewFieldError("body", "invalid request body")
+	}
+}
+
+func ruleMessage(fieldError validator.FieldError) string {
+	isString := fieldError.Kind() == reflect.String
+	isList := fieldError.Kind() == reflect.Slice
+	switch fieldError.Tag() {
+	case "required", "notblank":
+		return "is required"
+	case "max":
+		if isString {
+			return fmt.Sprintf("must be at most %s characters", fieldError.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fieldError.Param())
+	case "min":
+		if isString {
+			return fmt.Sprintf("must be at least %s characters", fieldError.Param())
+		}
+		if isList {
+			return fmt.Sprintf("must have at least %s items", fieldError.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fieldError.Param())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fieldError.Param())
+	case "datetime":
+		return "must be a date in the YYYY-MM-DD format"
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", strings.ReplaceAll(fieldError.Param(), " ", ", "))
+	case "uuid":
+		return "must be a valid UUID"
+	case "unique":
+		return "must not repeat items"
+	default:
+		return fmt.Sprintf("failed the %s rule", fieldError.Tag())
+	}
+}
+
+func typeName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	default:
+		return "a " + kind.String()
+	}
+}
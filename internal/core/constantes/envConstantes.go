@@ -5,4 +5,33 @@ const (
 	DbSource         = "DB_SOURCE"
 	Messaging        = "MESSAGING"
 	MessagingChannel = "MESSAGING_CHANNEL"
+	MessagingTimeout = "MESSAGING_CONNECT_TIMEOUT"
+	QueryTimeout     = "QUERY_TIMEOUT"
+	MigrateOnStart   = "MIGRATE_ON_START"
+	ShutdownTimeout  = "SHUTDOWN_TIMEOUT"
+
+	DbMaxOpenConns    = "DB_MAX_OPEN_CONNS"
+	DbMaxIdleConns    = "DB_MAX_IDLE_CONNS"
+	DbConnMaxLifetime = "DB_CONN_MAX_LIFETIME"
+	DbConnMaxIdleTime = "DB_CONN_MAX_IDLE_TIME"
+	DbConnectTimeout  = "DB_CONNECT_TIMEOUT"
+
+	TracingExporter = "TRACING_EXPORTER"
+
+	AdminToken = "ADMIN_TOKEN"
+
+	RatingWeight         = "RATING_WEIGHT"
+	RatingRebuildTimeout = "RATING_REBUILD_TIMEOUT"
+
+	ConfigFile = "CONFIG_FILE"
 )
+
+// Values accepted by TRACING_EXPORTER.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Exporters lists the values accepted by TRACING_EXPORTER.
+var Exporters = []string{ExporterNone, ExporterStdout, ExporterOTLP}
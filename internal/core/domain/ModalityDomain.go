@@ -2,13 +2,14 @@ package domain
 
 type Modality struct {
 	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	Amount_play int    `json:"amount_play"`
+	Name        string `json:"name" binding:"required,notblank,max=50"`
+	Amount_play int    `json:"amount_play" binding:"required,gt=0"`
 	Active      bool   `json:"active"`
+	Version     int    `json:"version"`
 }
 
 type CreateModalityRequest struct {
-	Name        string `json:"name"`
-	Amount_play int    `json:"amount_play"`
+	Name        string `json:"name" binding:"required,notblank,max=50"`
+	Amount_play int    `json:"amount_play" binding:"required,gt=0"`
 	Active      bool   `json:"active"`
 }
@@ -9,14 +9,48 @@ type Attributes struct {
 	DRI        int `json:"dri"`
 	DEF        int `json:"def"`
 	PHY        int `json:"phy"`
+	Version    int `json:"version"`
 }
 
 type AttributesRequest struct {
-	IDPosition int `json:"id_position"`
-	PAC        int `json:"pac"`
-	SHO        int `json:"sho"`
-	PAS        int `json:"pas"`
-	DRI        int `json:"dri"`
-	DEF        int `json:"def"`
-	PHY        int `json:"phy"`
+	IDPosition int `json:"id_position" binding:"required,gt=0"`
+	PAC        int `json:"pac" binding:"min=0"`
+	SHO        int `json:"sho" binding:"min=0"`
+	PAS        int `json:"pas" binding:"min=0"`
+	DRI        int `json:"dri" binding:"min=0"`
+	DEF        int `json:"def" binding:"min=0"`
+	PHY        int `json:"phy" binding:"min=0"`
+}
+
+// AttributesWeightTotal is what the weights of a position must add up to, so
+// that an overall stays on the same 1..99 scale as the card stats.
+const AttributesWeightTotal = 100
+
+func (a AttributesRequest) Validate() error {
+	var errs ValidationErrors
+	if a.IDPosition <= 0 {
+		errs.add("id_position", "is required")
+	}
+	weights := []struct {
+		field string
+		value int
+	}{
+		{"pac", a.PAC},
+		{"sho", a.SHO},
+		{"pas", a.PAS},
+		{"dri", a.DRI},
+		{"def", a.DEF},
+		{"phy", a.PHY},
+	}
+	total := 0
+	for _, weight := range weights {
+		if weight.value < 0 {
+			errs.add(weight.field, "must not be negative")
+		}
+		total += weight.value
+	}
+	if total != AttributesWeightTotal {
+		errs.add("weights", "must sum to %d, got %d", AttributesWeightTotal, total)
+	}
+	return errs.orNil()
 }
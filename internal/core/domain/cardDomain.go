@@ -3,21 +3,48 @@ package domain
 import "github.com/google/uuid"
 
 type Card struct {
-	ID     uuid.UUID `json:"id"`
-	IDPlay uuid.UUID `json:"id_play"`
-	PAC    int       `json:"pac"`
-	SHO    int       `json:"sho"`
-	PAS    int       `json:"pas"`
-	DRI    int       `json:"dri"`
-	DEF    int       `json:"def"`
-	PHY    int       `json:"phy"`
+	ID      uuid.UUID `json:"id"`
+	IDPlay  uuid.UUID `json:"id_play"`
+	PAC     int       `json:"pac"`
+	SHO     int       `json:"sho"`
+	PAS     int       `json:"pas"`
+	DRI     int       `json:"dri"`
+	DEF     int       `json:"def"`
+	PHY     int       `json:"phy"`
+	Version int       `json:"version"`
 }
 
 type CardRequest struct {
-	PAC int `json:"pac"`
-	SHO int `json:"sho"`
-	PAS int `json:"pas"`
-	DRI int `json:"dri"`
-	DEF int `json:"def"`
-	PHY int `json:"phy"`
+	PAC int `json:"pac" binding:"min=1,max=99"`
+	SHO int `json:"sho" binding:"min=1,max=99"`
+	PAS int `json:"pas" binding:"min=1,max=99"`
+	DRI int `json:"dri" binding:"min=1,max=99"`
+	DEF int `json:"def" binding:"min=1,max=99"`
+	PHY int `json:"phy" binding:"min=1,max=99"`
+}
+
+const (
+	MinStat = 1
+	MaxStat = 99
+)
+
+func (c CardRequest) Validate() error {
+	var errs ValidationErrors
+	stats := []struct {
+		field string
+		value int
+	}{
+		{"pac", c.PAC},
+		{"sho", c.SHO},
+		{"pas", c.PAS},
+		{"dri", c.DRI},
+		{"def", c.DEF},
+		{"phy", c.PHY},
+	}
+	for _, stat := range stats {
+		if stat.value < MinStat || stat.value > MaxStat {
+			errs.add(stat.field, "must be between %d and %d", MinStat, MaxStat)
+		}
+	}
+	return errs.orNil()
 }
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds shared by repositories and use cases. Transports map them to
+// their own status codes, so callers should test for them with errors.Is.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrPreconditionFailed reports a write made against a version of a
+	// record that is no longer the current one.
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func NewNotFoundError(format string, args ...any) error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewConflictError(format string, args ...any) error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewUnauthorizedError(format string, args ...any) error {
+	return &Error{Kind: ErrUnauthorized, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewPreconditionFailedError(format string, args ...any) error {
+	return &Error{Kind: ErrPreconditionFailed, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewFieldError is a ValidationErrors holding a single invalid field.
+func NewFieldError(field, format string, args ...any) error {
+	var errs ValidationErrors
+	errs.add(field, format, args...)
+	return errs
+}
@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// OutOfPositionPenalty is the share of strength a player loses for each line
+// between their position and the slot they fill. A player whose position is
+// not in the formation counts as being as far as the formation has lines.
+const OutOfPositionPenalty = 0.1
+
+type Formation struct {
+	ID         int             `json:"id"`
+	IDModality int             `json:"id_modality"`
+	Name       string          `json:"name"`
+	Lines      []FormationLine `json:"lines"`
+}
+
+// FormationLine is one line of a formation, listed from back to front.
+type FormationLine struct {
+	IDPosition int `json:"id_position" binding:"required,gt=0"`
+	Slots      int `json:"slots" binding:"required,gt=0"`
+}
+
+// Size is the number of players a side needs to fill the formation.
+func (f Formation) Size() int {
+	var size int
+	for _, line := range f.Lines {
+		size += line.Slots
+	}
+	return size
+}
+
+// distance counts the lines between a player's position and line.
+func (f Formation) distance(idPosition, line int) int {
+	for i, l := range f.Lines {
+		if l.IDPosition == idPosition {
+			return int(math.Abs(float64(i - line)))
+		}
+	}
+	return len(f.Lines)
+}
+
+// formationTemplate is a default formation whose lines name their position
+// by acronym.
+type formationTemplate struct {
+	name  string
+	lines []templateLine
+}
+
+type templateLine struct {
+	acronym string
+	slots   int
+}
+
+// defaultFormations are the templates a new modality gets, by amount_play.
+// Migration 0006 seeds the same ones for the modalities that existed before
+// it.
+var defaultFormations = map[int]formationTemplate{
+	5: {"1-2-2", []templateLine{{"GOL", 1}, {"ZAG", 2}, {"ATA", 2}}},
+	7: {"1-3-3", []templateLine{{"GOL", 1}, {"ZAG", 3}, {"ATA", 3}}},
+}
+
+// DefaultFormation returns the default template for a modality of amountPlay
+// players, its lines taken by the positions with the template's acronyms
+// (the lowest ID when several share one). It reports false when there is no
+// template of that size or one of its positions does not exist.
+func DefaultFormation(idModality, amountPlay int, positions []Position) (CreateFormationRequest, bool) {
+	template, ok := defaultFormations[amountPlay]
+	if !ok {
+		return CreateFormationRequest{}, false
+	}
+	formation := CreateFormationRequest{IDModality: idModality, Name: template.name}
+	for _, line := range template.lines {
+		idPosition := 0
+		for _, position := range positions {
+			if strings.EqualFold(position.Acronym, line.acronym) && (idPosition == 0 || position.ID < idPosition) {
+				idPosition = position.ID
+			}
+		}
+		if idPosition == 0 {
+			return CreateFormationRequest{}, false
+		}
+		formation.Lines = append(formation.Lines, FormationLine{IDPosition: idPosition, Slots: line.slots})
+	}
+	return formation, true
+}
+
+type CreateFormationRequest struct {
+	IDModality int             `json:"id_modality" binding:"required,gt=0"`
+	Name       string          `json:"name" binding:"required,notblank,max=20"`
+	Lines      []FormationLine `json:"lines" binding:"required,min=1,unique=IDPosition,dive"`
+}
+
+// LineupRequest lists the players drafted for a match, two sides' worth.
+type LineupRequest struct {
+	Players []uuid.UUID `json:"players" binding:"required,min=2,unique"`
+}
+
+// LineupPlayer is a drafted player as the lineup builder sees them.
+// Strength is their overall blended with their rating (see BlendRatings).
+type LineupPlayer struct {
+	IDPlay     uuid.UUID
+	Name       string
+	IDPosition int
+	Overall    *int
+	Strength   float64
+}
+
+// LineupSlot is a player placed in a line of the formation. Effective is
+// their strength after the out of position penalty.
+type LineupSlot struct {
+	IDPosition     int       `json:"id_position"`
+	IDPlay         uuid.UUID `json:"id_play"`
+	Name           string    `json:"name"`
+	IDPlayPosition int       `json:"id_play_position"`
+	Overall        int       `json:"overall"`
+	Strength       float64   `json:"strength"`
+	Effective      float64   `json:"effective_strength"`
+	OutOfPosition  bool      `json:"out_of_position"`
+}
+
+// Lineup is one side of a draft. Strength is the mean effective strength.
+type Lineup struct {
+	Strength float64      `json:"strength"`
+	Slots    []LineupSlot `json:"slots"`
+}
+
+// Draft is the two sides built for a formation. Difference is the home minus
+// away strength.
+type Draft struct {
+	IDFormation int     `json:"id_formation"`
+	Home        Lineup  `json:"home"`
+	Away        Lineup  `json:"away"`
+	Difference  float64 `json:"difference"`
+}
+
+// BuildDraft splits players, who must all have an overall and a strength and
+// number twice the formation's size, into two sides that each fill every line of it.
+//
+// Players are first placed in lines, strongest first, each line taking
+// players of its own position before it takes any from further away. Then
+// the players of every line are paired by effective strength and each pair is
+// split between the sides, widest gap first, giving the stronger player to
+// the side behind. No side ends up with all the strikers and no defenders.
+func BuildDraft(formation Formation, players []LineupPlayer) Draft {
+	players = append([]LineupPlayer(nil), players...)
+	sort.SliceStable(players, func(i, j int) bool { return players[i].Strength > players[j].Strength })
+
+	free := make([]int, len(formation.Lines))
+	for i, line := range formation.Lines {
+		free[i] = 2 * line.Slots
+	}
+	lines := make([][]LineupSlot, len(formation.Lines))
+	placed := make([]bool, len(players))
+	for distance := 0; distance <= len(formation.Lines); distance++ {
+		for i, player := range players {
+			if placed[i] {
+				continue
+			}
+			for line := range formation.Lines {
+				if free[line] == 0 || formation.distance(player.IDPosition, line) != distance {
+					continue
+				}
+				lines[line] = append(lines[line], lineupSlot(formation, line, player, distance))
+				free[line]--
+				placed[i] = true
+				break
+			}
+		}
+	}
+
+	type pair [2]LineupSlot
+	var pairs []pair
+	for _, slots := range lines {
+		sort.SliceStable(slots, func(i, j int) bool { return slots[i].Effective > slots[j].Effective })
+		for i := 0; i+1 < len(slots); i += 2 {
+			pairs = append(pairs, pair{slots[i], slots[i+1]})
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i][0].Effective-pairs[i][1].Effective > pairs[j][0].Effective-pairs[j][1].Effective
+	})
+
+	var sides [2][]LineupSlot
+	var totals [2]float64
+	for _, p := range pairs {
+		behind := 0
+		if totals[1] < totals[0] {
+			behind = 1
+		}
+		sides[behind] = append(sides[behind], p[0])
+		sides[1-behind] = append(sides[1-behind], p[1])
+		totals[behind] += p[0].Effective
+		totals[1-behind] += p[1].Effective
+	}
+
+	home, away := lineup(formation, sides[0]), lineup(formation, sides[1])
+	return Draft{
+		IDFormation: formation.ID,
+		Home:        home,
+		Away:        away,
+		Difference:  math.Round((home.Strength-away.Strength)*10) / 10,
+	}
+}
+
+func lineupSlot(formation Formation, line int, player LineupPlayer, distance int) LineupSlot {
+	effective := player.Strength * math.Max(0, 1-OutOfPositionPenalty*float64(distance))
+	return LineupSlot{
+		IDPosition:     formation.Lines[line].IDPosition,
+		IDPlay:         player.IDPlay,
+		Name:           player.Name,
+		IDPlayPosition: player.IDPosition,
+		Overall:        *player.Overall,
+		Strength:       player.Strength,
+		Effective:      math.Round(effective*10) / 10,
+		OutOfPosition:  distance > 0,
+	}
+}
+
+// lineup orders a side's slots as the formation's lines, strongest first
+// within a line.
+func lineup(formation Formation, slots []LineupSlot) Lineup {
+	order := make(map[int]int, len(formation.Lines))
+	for i, line := range formation.Lines {
+		order[line.IDPosition] = i
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		if order[slots[i].IDPosition] != order[slots[j].IDPosition] {
+			return order[slots[i].IDPosition] < order[slots[j].IDPosition]
+		}
+		return slots[i].Effective > slots[j].Effective
+	})
+	var total float64
+	for _, slot := range slots {
+		total += slot.Effective
+	}
+	var strength float64
+	if len(slots) > 0 {
+		strength = math.Round(total/float64(len(slots))*10) / 10
+	}
+	return Lineup{Strength: strength, Slots: slots}
+}
@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+const (
+	goalkeeper = 1
+	defender   = 2
+	attacker   = 3
+	unknown    = 9
+)
+
+// oneOfEach is a back-to-front formation with one goalkeeper, one defender
+// and one attacker per side.
+var oneOfEach = Formation{ID: 4, Lines: []FormationLine{{goalkeeper, 1}, {defender, 1}, {attacker, 1}}}
+
+func lineupPlayer(idPosition int, strength float64) LineupPlayer {
+	overall := int(strength)
+	return LineupPlayer{IDPlay: uuid.New(), IDPosition: idPosition, Overall: &overall, Strength: strength}
+}
+
+// slotsByLine counts a side's slots per line position.
+func slotsByLine(lineup Lineup) map[int]int {
+	counts := map[int]int{}
+	for _, slot := range lineup.Slots {
+		counts[slot.IDPosition]++
+	}
+	return counts
+}
+
+func TestBuildDraft(t *testing.T) {
+	tests := []struct {
+		name       string
+		players    []LineupPlayer
+		home, away float64
+		difference float64
+		// effective strength of the players filling a line out of position
+		outOfPosition []float64
+	}{
+		{
+			name: "everyone in position",
+			players: []LineupPlayer{
+				lineupPlayer(goalkeeper, 80), lineupPlayer(goalkeeper, 70),
+				lineupPlayer(defender, 75), lineupPlayer(defender, 65),
+				lineupPlayer(attacker, 90), lineupPlayer(attacker, 60),
+			},
+			// The attackers are split first, 90 home; the side behind
+			// then takes the better goalkeeper and defender.
+			home: 75, away: 71.7, difference: 3.3,
+		},
+		{
+			name: "third attacker drops one line",
+			players: []LineupPlayer{
+				lineupPlayer(goalkeeper, 60), lineupPlayer(goalkeeper, 50),
+				lineupPlayer(defender, 40),
+				lineupPlayer(attacker, 90), lineupPlayer(attacker, 80), lineupPlayer(attacker, 70),
+			},
+			// 70 loses 10% playing one line back, then evens out the
+			// defence: it is the widest pair and is split first.
+			home: 64.3, away: 63.3, difference: 1,
+			outOfPosition: []float64{63},
+		},
+		{
+			name: "position missing from the formation",
+			players: []LineupPlayer{
+				lineupPlayer(goalkeeper, 60), lineupPlayer(goalkeeper, 60),
+				lineupPlayer(defender, 60), lineupPlayer(unknown, 80),
+				lineupPlayer(attacker, 60), lineupPlayer(attacker, 60),
+			},
+			// A position the formation lacks costs as much as its number
+			// of lines: 30%.
+			home: 60, away: 58.7, difference: 1.3,
+			outOfPosition: []float64{56},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draft := BuildDraft(oneOfEach, tt.players)
+			if draft.IDFormation != oneOfEach.ID {
+				t.Errorf("IDFormation = %d, want %d", draft.IDFormation, oneOfEach.ID)
+			}
+			want := map[int]int{goalkeeper: 1, defender: 1, attacker: 1}
+			for side, lineup := range map[string]Lineup{"home": draft.Home, "away": draft.Away} {
+				if got := slotsByLine(lineup); !reflect.DeepEqual(got, want) {
+					t.Errorf("%s lines = %v, want one of each", side, got)
+				}
+				for i, slot := range lineup.Slots {
+					if slot.IDPosition != oneOfEach.Lines[i].IDPosition {
+						t.Errorf("%s slot %d is in line %d, want back to front", side, i, slot.IDPosition)
+					}
+				}
+			}
+			if draft.Home.Strength != tt.home || draft.Away.Strength != tt.away || draft.Difference != tt.difference {
+				t.Errorf("strength %v vs %v (difference %v), want %v vs %v (%v)",
+					draft.Home.Strength, draft.Away.Strength, draft.Difference, tt.home, tt.away, tt.difference)
+			}
+
+			var outOfPosition []float64
+			for _, slot := range append(draft.Home.Slots, draft.Away.Slots...) {
+				if slot.OutOfPosition != (slot.IDPlayPosition != slot.IDPosition) {
+					t.Errorf("slot = %+v", slot)
+				}
+				if slot.OutOfPosition {
+					outOfPosition = append(outOfPosition, slot.Effective)
+				} else if slot.Effective != slot.Strength {
+					t.Errorf("in position slot lost strength: %+v", slot)
+				}
+			}
+			if !reflect.DeepEqual(outOfPosition, tt.outOfPosition) {
+				t.Errorf("out of position effective strengths = %v, want %v", outOfPosition, tt.outOfPosition)
+			}
+		})
+	}
+}
+
+func TestBuildDraftKeepsInput(t *testing.T) {
+	players := []LineupPlayer{
+		lineupPlayer(goalkeeper, 50), lineupPlayer(goalkeeper, 60),
+		lineupPlayer(defender, 50), lineupPlayer(defender, 60),
+		lineupPlayer(attacker, 50), lineupPlayer(attacker, 60),
+	}
+	before := append([]LineupPlayer(nil), players...)
+	BuildDraft(oneOfEach, players)
+	if !reflect.DeepEqual(players, before) {
+		t.Errorf("BuildDraft reordered its input")
+	}
+}
+
+func TestDefaultFormation(t *testing.T) {
+	positions := []Position{
+		{ID: 7, Acronym: "GOL"},
+		{ID: 2, Acronym: "gol"},
+		{ID: 3, Acronym: "ZAG"},
+		{ID: 4, Acronym: "ATA"},
+	}
+	tests := []struct {
+		name       string
+		amountPlay int
+		positions  []Position
+		want       CreateFormationRequest
+		ok         bool
+	}{
+		{
+			name: "five a side", amountPlay: 5, positions: positions, ok: true,
+			want: CreateFormationRequest{IDModality: 1, Name: "1-2-2", Lines: []FormationLine{{2, 1}, {3, 2}, {4, 2}}},
+		},
+		{
+			name: "seven a side", amountPlay: 7, positions: positions, ok: true,
+			want: CreateFormationRequest{IDModality: 1, Name: "1-3-3", Lines: []FormationLine{{2, 1}, {3, 3}, {4, 3}}},
+		},
+		{name: "no template of that size", amountPlay: 6, positions: positions},
+		{name: "missing position", amountPlay: 5, positions: positions[:3]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DefaultFormation(1, tt.amountPlay, tt.positions)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("DefaultFormation = %+v, %v; want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+			if ok && (Formation{Lines: got.Lines}).Size() != tt.amountPlay {
+				t.Errorf("template does not fill %d players", tt.amountPlay)
+			}
+		})
+	}
+}
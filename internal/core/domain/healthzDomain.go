@@ -1,7 +1,20 @@
 package domain
 
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusDown = "down"
+)
+
 type Healthz struct {
-	Message string  `json:"Message"`
-	Version float32 `json:"version"`
-	Status  string  `json:"Status"`
+	Message string                 `json:"message"`
+	Status  string                 `json:"status"`
+	Version string                 `json:"version"`
+	Commit  string                 `json:"commit"`
+	Checks  map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+type HealthCheck struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
 }
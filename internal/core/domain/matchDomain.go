@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The two sides of a match.
+const (
+	TeamHome = "home"
+	TeamAway = "away"
+)
+
+type Match struct {
+	ID         uuid.UUID     `json:"id"`
+	IDSeason   int           `json:"id_season"`
+	IDModality int           `json:"id_modality"`
+	PlayedAt   time.Time     `json:"played_at"`
+	HomeGoals  *int          `json:"home_goals"`
+	AwayGoals  *int          `json:"away_goals"`
+	Version    int           `json:"version"`
+	Players    []MatchPlayer `json:"players,omitempty"`
+}
+
+// Finished reports whether the result of the match was recorded.
+func (m Match) Finished() bool {
+	return m.HomeGoals != nil && m.AwayGoals != nil
+}
+
+// Team returns the side id played for, or "" when they were not in the
+// match.
+func (m Match) Team(id uuid.UUID) string {
+	for _, player := range m.Players {
+		if player.IDPlay == id {
+			return player.Team
+		}
+	}
+	return ""
+}
+
+// MatchPlayer is one player of a match. Overall is theirs at the time the
+// match was registered.
+type MatchPlayer struct {
+	IDPlay  uuid.UUID `json:"id_play"`
+	Name    string    `json:"name"`
+	Team    string    `json:"team"`
+	Overall *int      `json:"overall"`
+	Goals   int       `json:"goals"`
+	Assists int       `json:"assists"`
+}
+
+type CreateMatchRequest struct {
+	IDSeason   int         `json:"id_season" binding:"required,gt=0"`
+	IDModality int         `json:"id_modality" binding:"required,gt=0"`
+	PlayedAt   time.Time   `json:"played_at" binding:"required"`
+	Home       []uuid.UUID `json:"home" binding:"required,min=1,unique"`
+	Away       []uuid.UUID `json:"away" binding:"required,min=1,unique"`
+}
+
+// MatchResultRequest records the score and, for the players listed, their
+// goals and assists. Players left out are recorded with none.
+type MatchResultRequest struct {
+	HomeGoals *int                `json:"home_goals" binding:"required,min=0"`
+	AwayGoals *int                `json:"away_goals" binding:"required,min=0"`
+	Players   []MatchPlayerResult `json:"players" binding:"dive"`
+}
+
+type MatchPlayerResult struct {
+	IDPlay  uuid.UUID `json:"id_play" binding:"required"`
+	Goals   int       `json:"goals" binding:"min=0"`
+	Assists int       `json:"assists" binding:"min=0"`
+}
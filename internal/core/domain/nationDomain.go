@@ -2,11 +2,12 @@ package domain
 
 type Nation struct {
 	ID      int    `json:"id"`
-	Name    string `json:"name"`
-	Acronym string `json:"acronym"`
+	Name    string `json:"name" binding:"required,notblank,max=50"`
+	Acronym string `json:"acronym" binding:"required,max=3"`
+	Version int    `json:"version"`
 }
 
 type CreateNationRequest struct {
-	Name    string `json:"name"`
-	Acronym string `json:"acronym"`
+	Name    string `json:"name" binding:"required,notblank,max=50"`
+	Acronym string `json:"acronym" binding:"required,max=3"`
 }
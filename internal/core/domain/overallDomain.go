@@ -1,6 +1,23 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// OverallMessageVersion is the schema version stamped on every overall
+// message published or expected by this service.
+const OverallMessageVersion = 2
+
+const (
+	MinOverall = 1
+	MaxOverall = 99
+)
+
+var ErrInvalidOverallMessage = errors.New("invalid overall message")
 
 type Overall struct {
 	ID      uuid.UUID `json:"id"`
@@ -9,11 +26,69 @@ type Overall struct {
 }
 
 type OverallBodyRequest struct {
+	Version    int        `json:"version"`
 	Card       Card       `json:"card"`
 	Attributes Attributes `json:"attributes"`
 }
 
 type OverallRequest struct {
+	Version int       `json:"version"`
+	IDPlay  uuid.UUID `json:"id_play"`
+	Overall int       `json:"overall"`
+}
+
+// overallRequestV1 is the unversioned payload sent by the calculator before
+// messages carried a version; it identified the player as "id_user".
+type overallRequestV1 struct {
 	IDPlay  uuid.UUID `json:"id_play"`
+	IDUser  uuid.UUID `json:"id_user"`
 	Overall int       `json:"overall"`
 }
+
+// DecodeOverallRequest decodes an overall message of any supported version
+// into the current schema and validates it.
+func DecodeOverallRequest(body []byte) (OverallRequest, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(body, &header); err != nil {
+		return OverallRequest{}, fmt.Errorf("%w: %v", ErrInvalidOverallMessage, err)
+	}
+
+	var overallRequest OverallRequest
+	switch header.Version {
+	case 0, 1:
+		var legacy overallRequestV1
+		if err := json.Unmarshal(body, &legacy); err != nil {
+			return OverallRequest{}, fmt.Errorf("%w: %v", ErrInvalidOverallMessage, err)
+		}
+		overallRequest = OverallRequest{IDPlay: legacy.IDPlay, Overall: legacy.Overall}
+		if overallRequest.IDPlay == uuid.Nil {
+			overallRequest.IDPlay = legacy.IDUser
+		}
+	case OverallMessageVersion:
+		// Fields added by newer calculators are ignored; only the ones this
+		// service needs are validated.
+		if err := json.Unmarshal(body, &overallRequest); err != nil {
+			return OverallRequest{}, fmt.Errorf("%w: %v", ErrInvalidOverallMessage, err)
+		}
+	default:
+		return OverallRequest{}, fmt.Errorf("%w: unsupported version %d", ErrInvalidOverallMessage, header.Version)
+	}
+	overallRequest.Version = OverallMessageVersion
+
+	if err := overallRequest.Validate(); err != nil {
+		return OverallRequest{}, err
+	}
+	return overallRequest, nil
+}
+
+func (o OverallRequest) Validate() error {
+	if o.IDPlay == uuid.Nil {
+		return fmt.Errorf("%w: id_play is required", ErrInvalidOverallMessage)
+	}
+	if o.Overall < MinOverall || o.Overall > MaxOverall {
+		return fmt.Errorf("%w: overall must be between %d and %d, got %d", ErrInvalidOverallMessage, MinOverall, MaxOverall, o.Overall)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDecodeOverallRequest(t *testing.T) {
+	id := uuid.MustParse("3f2b8c1e-0d4a-4c5e-9a7b-1c2d3e4f5a6b")
+	other := uuid.MustParse("7d9e6f10-2b3c-4d5e-8f90-a1b2c3d4e5f6")
+
+	tests := []struct {
+		name    string
+		body    string
+		want    OverallRequest
+		invalid bool
+	}{
+		{
+			name: "v0 with id_play",
+			body: `{"id_play":"` + id.String() + `","overall":80}`,
+			want: OverallRequest{Version: OverallMessageVersion, IDPlay: id, Overall: 80},
+		},
+		{
+			name: "v0 with legacy id_user",
+			body: `{"id_user":"` + id.String() + `","overall":80}`,
+			want: OverallRequest{Version: OverallMessageVersion, IDPlay: id, Overall: 80},
+		},
+		{
+			name: "v1 prefers id_play over id_user",
+			body: `{"version":1,"id_play":"` + id.String() + `","id_user":"` + other.String() + `","overall":80}`,
+			want: OverallRequest{Version: OverallMessageVersion, IDPlay: id, Overall: 80},
+		},
+		{
+			name: "v2",
+			body: `{"version":2,"id_play":"` + id.String() + `","overall":75}`,
+			want: OverallRequest{Version: OverallMessageVersion, IDPlay: id, Overall: 75},
+		},
+		{
+			name: "v2 ignores unknown fields",
+			body: `{"version":2,"id_play":"` + id.String() + `","overall":75,"source":"calculator"}`,
+			want: OverallRequest{Version: OverallMessageVersion, IDPlay: id, Overall: 75},
+		},
+		{
+			name:    "v2 ignores legacy id_user",
+			body:    `{"version":2,"id_user":"` + id.String() + `","overall":75}`,
+			invalid: true,
+		},
+		{name: "lower bound", body: `{"version":2,"id_play":"` + id.String() + `","overall":1}`, want: OverallRequest{Version: OverallMessageVersion, IDPlay: id, Overall: 1}},
+		{name: "upper bound", body: `{"version":2,"id_play":"` + id.String() + `","overall":99}`, want: OverallRequest{Version: OverallMessageVersion, IDPlay: id, Overall: 99}},
+		{name: "below range", body: `{"version":2,"id_play":"` + id.String() + `","overall":0}`, invalid: true},
+		{name: "above range", body: `{"version":2,"id_play":"` + id.String() + `","overall":100}`, invalid: true},
+		{name: "missing player", body: `{"version":2,"overall":80}`, invalid: true},
+		{name: "nil player", body: `{"version":2,"id_play":"` + uuid.Nil.String() + `","overall":80}`, invalid: true},
+		{name: "bad player", body: `{"version":2,"id_play":"player-1","overall":80}`, invalid: true},
+		{name: "unsupported version", body: `{"version":3,"id_play":"` + id.String() + `","overall":80}`, invalid: true},
+		{name: "not json", body: `overall=80`, invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeOverallRequest([]byte(tt.body))
+			if tt.invalid {
+				if !errors.Is(err, ErrInvalidOverallMessage) {
+					t.Fatalf("err = %v, want ErrInvalidOverallMessage", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,20 +1,38 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Play struct {
 	ID         uuid.UUID `json:"id"`
-	Name       string    `json:"name"`
-	IDPosition int       `json:"id_position"`
-	IDNation   int       `json:"id_nation"`
+	Name       string    `json:"name" binding:"required,notblank,max=50"`
+	IDPosition int       `json:"id_position" binding:"required,gt=0"`
+	IDNation   int       `json:"id_nation" binding:"required,gt=0"`
 	Field      bool      `json:"field"`
 	Active     bool      `json:"active"`
+	Version    int       `json:"version"`
 }
 
 type CreatePlayRequest struct {
-	Name       string `json:"name"`
-	IDPosition int    `json:"id_position"`
-	IDNation   int    `json:"id_nation"`
+	Name       string `json:"name" binding:"required,notblank,max=50"`
+	IDPosition int    `json:"id_position" binding:"required,gt=0"`
+	IDNation   int    `json:"id_nation" binding:"required,gt=0"`
 	Field      bool   `json:"field"`
 	Active     bool   `json:"active"`
 }
+
+// PlayDeletedMessageVersion is the schema version stamped on every play
+// deletion message.
+const PlayDeletedMessageVersion = 1
+
+// PlayDeletedEvent is published once a play and everything attached to it
+// has been permanently removed, so other services can drop their copies. It
+// carries no personal data on purpose.
+type PlayDeletedEvent struct {
+	Version   int       `json:"version"`
+	IDPlay    uuid.UUID `json:"id_play"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
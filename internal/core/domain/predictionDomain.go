@@ -0,0 +1,320 @@
+package domain
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// Prediction model settings. Below MinCalibrationMatches recorded matches
+// the model is not fitted and the default weights and spread are used.
+const (
+	MinCalibrationMatches = 10
+
+	// predictionRidge keeps the fitted weights small while there are few
+	// matches to learn from.
+	predictionRidge = 1.0
+
+	defaultPredictionSigma = 2.0
+	minPredictionSigma     = 1.0
+)
+
+// defaultPredictionWeights are the goals per point of each feature: a tenth
+// of a goal per overall point, the same per rating point converted to the
+// overall scale, and nothing for the profile until results say otherwise.
+var defaultPredictionWeights = PredictionFeatures{0.1, 0.1, 0}
+
+// PredictionPlayer is what the prediction knows about one player of a match:
+// their overall when the match was registered, their rating going into it
+// and their current card stats (nil without a card).
+type PredictionPlayer struct {
+	Team    string
+	Overall *int
+	Rating  float64
+	Stats   []int
+}
+
+// PredictionMatch is a match as seen by the prediction model. The score is
+// nil until the match is finished.
+type PredictionMatch struct {
+	ID        uuid.UUID
+	HomeGoals *int
+	AwayGoals *int
+	Players   []PredictionPlayer
+}
+
+// Drafted reports whether both sides of the match have players, without
+// which there is nothing to compare.
+func (m PredictionMatch) Drafted() bool {
+	var sides [2]bool
+	for _, player := range m.Players {
+		sides[sideIndex(player.Team)] = true
+	}
+	return sides[0] && sides[1]
+}
+
+// PredictionFeatures are home minus away differences of: mean overall, mean
+// rating on the overall scale, and profile, the mean of the side's weakest
+// card stat, which drops for a side with, say, no defenders.
+type PredictionFeatures [3]float64
+
+// MarshalJSON names the features.
+func (f PredictionFeatures) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]float64{"overall": f[0], "rating": f[1], "profile": f[2]})
+}
+
+// Features computes the model inputs of the match.
+func (m PredictionMatch) Features() PredictionFeatures {
+	var sides [2]struct {
+		overall, overalls float64
+		rating, players   float64
+		stats             [6]float64
+		cards             float64
+	}
+	for _, player := range m.Players {
+		side := &sides[sideIndex(player.Team)]
+		side.players++
+		side.rating += player.Rating
+		if player.Overall != nil {
+			side.overall += float64(*player.Overall)
+			side.overalls++
+		}
+		if len(player.Stats) == len(side.stats) {
+			for i, stat := range player.Stats {
+				side.stats[i] += float64(stat)
+			}
+			side.cards++
+		}
+	}
+
+	var features PredictionFeatures
+	if sides[0].overalls > 0 && sides[1].overalls > 0 {
+		features[0] = sides[0].overall/sides[0].overalls - sides[1].overall/sides[1].overalls
+	}
+	if sides[0].players > 0 && sides[1].players > 0 {
+		features[1] = (sides[0].rating/sides[0].players - sides[1].rating/sides[1].players) / RatingPointsPerOverall
+	}
+	if sides[0].cards > 0 && sides[1].cards > 0 {
+		weakest := func(i int) float64 {
+			low := math.Inf(1)
+			for _, stat := range sides[i].stats {
+				low = math.Min(low, stat/sides[i].cards)
+			}
+			return low
+		}
+		features[2] = weakest(0) - weakest(1)
+	}
+	return features
+}
+
+// GoalDifference is the home minus away score of a finished match.
+func (m PredictionMatch) GoalDifference() int {
+	return *m.HomeGoals - *m.AwayGoals
+}
+
+// PredictionModel turns features into an expected goal difference, which is
+// assumed normally distributed with spread Sigma around it.
+type PredictionModel struct {
+	Weights PredictionFeatures
+	Sigma   float64
+}
+
+// DefaultPredictionModel is used while there are too few results to fit one.
+func DefaultPredictionModel() PredictionModel {
+	return PredictionModel{Weights: defaultPredictionWeights, Sigma: defaultPredictionSigma}
+}
+
+// FitPredictionModel fits the weights to the goal differences of finished
+// matches by ridge regression, and Sigma to the spread of what is left, once
+// there are MinCalibrationMatches of them.
+func FitPredictionModel(matches []PredictionMatch) PredictionModel {
+	if !canFit(len(matches)) {
+		return DefaultPredictionModel()
+	}
+	return fitPredictionModel(matches)
+}
+
+// canFit is the one threshold between the default and the fitted model.
+func canFit(matches int) bool {
+	return matches >= MinCalibrationMatches
+}
+
+func fitPredictionModel(matches []PredictionMatch) PredictionModel {
+	var a [3][3]float64
+	var b [3]float64
+	features := make([]PredictionFeatures, len(matches))
+	for n, match := range matches {
+		features[n] = match.Features()
+		for i := range 3 {
+			for j := range 3 {
+				a[i][j] += features[n][i] * features[n][j]
+			}
+			b[i] += features[n][i] * float64(match.GoalDifference())
+		}
+	}
+	for i := range 3 {
+		a[i][i] += predictionRidge
+	}
+	model := PredictionModel{Weights: solve3(a, b)}
+
+	var squares float64
+	for n, match := range matches {
+		residual := float64(match.GoalDifference()) - model.expected(features[n])
+		squares += residual * residual
+	}
+	model.Sigma = math.Max(math.Sqrt(squares/float64(len(matches))), minPredictionSigma)
+	return model
+}
+
+func (p PredictionModel) expected(features PredictionFeatures) float64 {
+	var sum float64
+	for i, weight := range p.Weights {
+		sum += weight * features[i]
+	}
+	return sum
+}
+
+// Predict returns the outcome probabilities of a match with these features:
+// a goal difference above half a goal is a home win, below minus half an
+// away win, anything in between a draw.
+func (p PredictionModel) Predict(features PredictionFeatures) Outcomes {
+	expected := p.expected(features)
+	awayWin := normalCDF((-0.5 - expected) / p.Sigma)
+	homeWin := 1 - normalCDF((0.5-expected)/p.Sigma)
+	return Outcomes{
+		HomeWin:                homeWin,
+		Draw:                   1 - homeWin - awayWin,
+		AwayWin:                awayWin,
+		ExpectedGoalDifference: expected,
+	}
+}
+
+type Outcomes struct {
+	HomeWin                float64 `json:"home_win"`
+	Draw                   float64 `json:"draw"`
+	AwayWin                float64 `json:"away_win"`
+	ExpectedGoalDifference float64 `json:"expected_goal_difference"`
+}
+
+// Likeliest names the most probable outcome from the home side's view.
+func (o Outcomes) Likeliest() string {
+	switch {
+	case o.HomeWin >= o.Draw && o.HomeWin >= o.AwayWin:
+		return OutcomeWin
+	case o.AwayWin > o.Draw:
+		return OutcomeLoss
+	default:
+		return OutcomeDraw
+	}
+}
+
+// Calibration reports how well the model predicts the recorded matches, each
+// one predicted by a model fitted on all the others (leave-one-out).
+// Accuracy is the share whose likeliest outcome happened; BrierScore is the
+// mean squared error of the three probabilities (0 is perfect, 2/3 is what
+// always answering a third each scores). Fitted reports whether predictions
+// come from a fitted model rather than the defaults; the two scores are nil
+// until they do, since they would only grade the defaults.
+type Calibration struct {
+	Matches    int      `json:"matches"`
+	Fitted     bool     `json:"fitted"`
+	Accuracy   *float64 `json:"accuracy"`
+	BrierScore *float64 `json:"brier_score"`
+}
+
+// outcomeIndex orders outcomes as Outcomes does: home win, draw, away win.
+var outcomeIndex = map[string]int{OutcomeWin: 0, OutcomeDraw: 1, OutcomeLoss: 2}
+
+// Calibrate evaluates the fitted model on matches by leave-one-out. Each
+// held-out match is predicted by a model fitted on the others even when they
+// fall one short of MinCalibrationMatches, so the scores grade the model
+// that is actually in use.
+func Calibrate(matches []PredictionMatch) Calibration {
+	calibration := Calibration{Matches: len(matches), Fitted: canFit(len(matches))}
+	if !calibration.Fitted {
+		return calibration
+	}
+
+	var hits, brier float64
+	others := make([]PredictionMatch, 0, len(matches)-1)
+	for n, match := range matches {
+		others = append(append(others[:0], matches[:n]...), matches[n+1:]...)
+		outcomes := fitPredictionModel(others).Predict(match.Features())
+
+		actual := [3]float64{}
+		switch diff := match.GoalDifference(); {
+		case diff > 0:
+			actual[0] = 1
+		case diff < 0:
+			actual[2] = 1
+		default:
+			actual[1] = 1
+		}
+		predicted := [3]float64{outcomes.HomeWin, outcomes.Draw, outcomes.AwayWin}
+		for i := range 3 {
+			brier += (predicted[i] - actual[i]) * (predicted[i] - actual[i])
+		}
+		if actual[outcomeIndex[outcomes.Likeliest()]] == 1 {
+			hits++
+		}
+	}
+	accuracy := hits / float64(len(matches))
+	brier /= float64(len(matches))
+	calibration.Accuracy, calibration.BrierScore = &accuracy, &brier
+	return calibration
+}
+
+// Prediction is the forecast of one match with what it was based on.
+type Prediction struct {
+	IDMatch uuid.UUID `json:"id_match"`
+	Outcomes
+	Features    PredictionFeatures `json:"features"`
+	Calibration Calibration        `json:"calibration"`
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// solve3 solves a·x = b by Gaussian elimination with partial pivoting. The
+// ridge term keeps a well conditioned; should a still be singular, the
+// components it leaves undetermined are 0 rather than NaN.
+func solve3(a [3][3]float64, b [3]float64) PredictionFeatures {
+	for col := range 3 {
+		pivot := col
+		for row := col + 1; row < 3; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+		if singular(a[col][col]) {
+			continue
+		}
+		for row := col + 1; row < 3; row++ {
+			factor := a[row][col] / a[col][col]
+			for k := col; k < 3; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+			b[row] -= factor * b[col]
+		}
+	}
+	var x PredictionFeatures
+	for row := 2; row >= 0; row-- {
+		if singular(a[row][row]) {
+			continue
+		}
+		sum := b[row]
+		for k := row + 1; k < 3; k++ {
+			sum -= a[row][k] * x[k]
+		}
+		x[row] = sum / a[row][row]
+	}
+	return x
+}
+
+func singular(pivot float64) bool {
+	return math.Abs(pivot) < 1e-12
+}
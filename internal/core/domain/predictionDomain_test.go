@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+// predictionMatch is a finished one-a-side match whose home player is diff
+// overall points better, ended with the given score.
+func predictionMatch(diff, homeGoals, awayGoals int) PredictionMatch {
+	home, away := 70+diff, 70
+	return PredictionMatch{
+		HomeGoals: &homeGoals,
+		AwayGoals: &awayGoals,
+		Players: []PredictionPlayer{
+			{Team: TeamHome, Overall: &home, Rating: InitialRating},
+			{Team: TeamAway, Overall: &away, Rating: InitialRating},
+		},
+	}
+}
+
+// decisiveHistory has n matches in which every 5 overall points are worth
+// exactly one goal.
+func decisiveHistory(n int) []PredictionMatch {
+	diffs := []int{10, -10, 5, -5, 0}
+	matches := make([]PredictionMatch, n)
+	for i := range matches {
+		diff := diffs[i%len(diffs)]
+		goals := diff / 5
+		matches[i] = predictionMatch(diff, 2+goals, 2)
+	}
+	return matches
+}
+
+func TestSolve3(t *testing.T) {
+	tests := []struct {
+		name string
+		a    [3][3]float64
+		b    [3]float64
+		want PredictionFeatures
+	}{
+		{"well conditioned", [3][3]float64{{2, 1, 0}, {1, 3, 1}, {0, 1, 4}}, [3]float64{4, 10, 14}, PredictionFeatures{1, 2, 3}},
+		{"needs pivoting", [3][3]float64{{0, 1, 0}, {1, 0, 0}, {0, 0, 1}}, [3]float64{2, 1, 3}, PredictionFeatures{1, 2, 3}},
+		{"singular", [3][3]float64{{1, 1, 0}, {1, 1, 0}, {0, 0, 2}}, [3]float64{2, 2, 4}, PredictionFeatures{2, 0, 2}},
+		{"zero", [3][3]float64{}, [3]float64{}, PredictionFeatures{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := solve3(tt.a, tt.b)
+			for i := range got {
+				if math.IsNaN(got[i]) || !closeTo(got[i], tt.want[i]) {
+					t.Fatalf("solve3 = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFitPredictionModelThreshold(t *testing.T) {
+	if model := FitPredictionModel(decisiveHistory(MinCalibrationMatches - 1)); model != DefaultPredictionModel() {
+		t.Errorf("fitted %+v below the threshold", model)
+	}
+	model := FitPredictionModel(decisiveHistory(MinCalibrationMatches))
+	if model == DefaultPredictionModel() {
+		t.Fatalf("did not fit at the threshold")
+	}
+	// Ridge shrinks the weight below the true 0.2 goals per overall point;
+	// the other features never vary and get none.
+	if model.Weights[0] <= 0.15 || model.Weights[0] >= 0.2 || model.Weights[1] != 0 || model.Weights[2] != 0 {
+		t.Errorf("weights = %v", model.Weights)
+	}
+	if model.Sigma != minPredictionSigma {
+		t.Errorf("sigma = %v, want the minimum %v", model.Sigma, minPredictionSigma)
+	}
+}
+
+func TestFitPredictionModelWithoutSignal(t *testing.T) {
+	// Every feature is 0, so only the ridge term keeps the system solvable.
+	matches := make([]PredictionMatch, MinCalibrationMatches)
+	for i := range matches {
+		matches[i] = predictionMatch(0, 3*(i%2), 0)
+	}
+	model := FitPredictionModel(matches)
+	if model.Weights != (PredictionFeatures{}) {
+		t.Errorf("weights = %v, want none", model.Weights)
+	}
+	// Half the matches end 3-0 and half 0-0: the spread is their RMS.
+	if !closeTo(model.Sigma, math.Sqrt(4.5)) {
+		t.Errorf("sigma = %v, want %v", model.Sigma, math.Sqrt(4.5))
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	short := Calibrate(decisiveHistory(MinCalibrationMatches - 1))
+	if short.Fitted || short.Matches != MinCalibrationMatches-1 || short.Accuracy != nil || short.BrierScore != nil {
+		t.Errorf("calibration below the threshold = %+v", short)
+	}
+
+	matches := decisiveHistory(MinCalibrationMatches)
+	calibration := Calibrate(matches)
+	if !calibration.Fitted || calibration.Matches != len(matches) || calibration.Accuracy == nil || calibration.BrierScore == nil {
+		t.Fatalf("calibration = %+v", calibration)
+	}
+	if *calibration.Accuracy != 1 {
+		t.Errorf("accuracy = %v, want every outcome called", *calibration.Accuracy)
+	}
+
+	// Each match is graded by a model that never saw it, which does worse
+	// than the model fitted on all of them.
+	var inSample float64
+	model := FitPredictionModel(matches)
+	for _, match := range matches {
+		inSample += brier(model.Predict(match.Features()), match)
+	}
+	inSample /= float64(len(matches))
+	if *calibration.BrierScore <= inSample {
+		t.Errorf("leave-one-out brier %v is not worse than in-sample %v", *calibration.BrierScore, inSample)
+	}
+}
+
+func brier(outcomes Outcomes, match PredictionMatch) float64 {
+	actual := [3]float64{}
+	switch diff := match.GoalDifference(); {
+	case diff > 0:
+		actual[0] = 1
+	case diff < 0:
+		actual[2] = 1
+	default:
+		actual[1] = 1
+	}
+	predicted := [3]float64{outcomes.HomeWin, outcomes.Draw, outcomes.AwayWin}
+	var sum float64
+	for i := range 3 {
+		sum += (predicted[i] - actual[i]) * (predicted[i] - actual[i])
+	}
+	return sum
+}
+
+func TestPredictionMatchDrafted(t *testing.T) {
+	player := func(team string) PredictionPlayer { return PredictionPlayer{Team: team} }
+	tests := []struct {
+		name    string
+		players []PredictionPlayer
+		want    bool
+	}{
+		{"no players", nil, false},
+		{"home only", []PredictionPlayer{player(TeamHome), player(TeamHome)}, false},
+		{"away only", []PredictionPlayer{player(TeamAway)}, false},
+		{"both sides", []PredictionPlayer{player(TeamAway), player(TeamHome)}, true},
+	}
+	for _, tt := range tests {
+		if got := (PredictionMatch{Players: tt.players}).Drafted(); got != tt.want {
+			t.Errorf("%s: Drafted = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Elo parameters. Every player starts at InitialRating; RatingK scales how
+// far one match moves a rating, and wider goal margins move it further.
+const (
+	InitialRating = 1500.0
+	RatingK       = 32.0
+
+	// RatingPointsPerOverall converts rating points to the overall scale
+	// when comparing the two: 20 rating points are worth one overall point,
+	// and InitialRating sits at the average overall.
+	RatingPointsPerOverall = 20.0
+)
+
+// Rating is a player's current result-based rating next to their card
+// overall. RatingOverall is the rating on the overall scale and Strength the
+// blend of both used for balancing teams.
+type Rating struct {
+	IDPlay        uuid.UUID `json:"id_play"`
+	Name          string    `json:"name"`
+	Rating        float64   `json:"rating"`
+	Games         int       `json:"games"`
+	Overall       *int      `json:"overall"`
+	RatingOverall *float64  `json:"rating_overall"`
+	Strength      *float64  `json:"strength"`
+}
+
+// RatingChange is the move of one player's rating caused by one match.
+type RatingChange struct {
+	IDPlay   uuid.UUID `json:"id_play"`
+	IDMatch  uuid.UUID `json:"id_match"`
+	PlayedAt time.Time `json:"played_at"`
+	Before   float64   `json:"rating_before"`
+	After    float64   `json:"rating_after"`
+}
+
+// ComputeRatings replays finished matches, oldest first, with RateMatch.
+// Unfinished matches are skipped.
+func ComputeRatings(matches []Match) []RatingChange {
+	ratings := map[uuid.UUID]float64{}
+	var changes []RatingChange
+	for _, match := range matches {
+		if !match.Finished() {
+			continue
+		}
+		for _, change := range RateMatch(ratings, match) {
+			ratings[change.IDPlay] = change.After
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// RateMatch is one step of a team Elo: a side's rating is the mean of its
+// players' current ratings (InitialRating for those missing from ratings),
+// the expected score follows the usual logistic curve, and every player of a
+// side moves by the same amount, scaled up for wider goal margins. match
+// must be finished; a side without players leaves everyone unchanged.
+func RateMatch(ratings map[uuid.UUID]float64, match Match) []RatingChange {
+	rating := func(id uuid.UUID) float64 {
+		if r, ok := ratings[id]; ok {
+			return r
+		}
+		return InitialRating
+	}
+
+	var sum, count [2]float64
+	for _, player := range match.Players {
+		side := sideIndex(player.Team)
+		sum[side] += rating(player.IDPlay)
+		count[side]++
+	}
+	if count[0] == 0 || count[1] == 0 {
+		return nil
+	}
+	home, away := sum[0]/count[0], sum[1]/count[1]
+
+	expected := ExpectedScore(home, away)
+	diff := *match.HomeGoals - *match.AwayGoals
+	score := 0.5
+	if diff > 0 {
+		score = 1
+	} else if diff < 0 {
+		score = 0
+	}
+	delta := RatingK * math.Log(math.Abs(float64(diff))+math.E) * (score - expected)
+
+	changes := make([]RatingChange, 0, len(match.Players))
+	for _, player := range match.Players {
+		before := rating(player.IDPlay)
+		after := before + delta
+		if player.Team == TeamAway {
+			after = before - delta
+		}
+		changes = append(changes, RatingChange{
+			IDPlay:   player.IDPlay,
+			IDMatch:  match.ID,
+			PlayedAt: match.PlayedAt,
+			Before:   before,
+			After:    after,
+		})
+	}
+	return changes
+}
+
+// BlendRatings fills in RatingOverall and Strength, rounded to one decimal.
+// ratings should hold every active player: the rating scale is anchored on
+// the mean of their overalls, so InitialRating maps to an average card.
+// Strength gives the rating weight (RATING_WEIGHT, 0 to 1) against the
+// overall, or all of it for a player without one. Both stay nil while no
+// player has an overall.
+func BlendRatings(ratings []Rating, weight float64) {
+	var sum, count float64
+	for _, rating := range ratings {
+		if rating.Overall != nil {
+			sum += float64(*rating.Overall)
+			count++
+		}
+	}
+	if count == 0 {
+		return
+	}
+	mean := sum / count
+	for i := range ratings {
+		ratingOverall := Round1(mean + (ratings[i].Rating-InitialRating)/RatingPointsPerOverall)
+		strength := ratingOverall
+		if ratings[i].Overall != nil {
+			strength = Round1((1-weight)*float64(*ratings[i].Overall) + weight*ratingOverall)
+		}
+		ratings[i].RatingOverall = &ratingOverall
+		ratings[i].Strength = &strength
+	}
+}
+
+// ExpectedScore is the score (1 win, 0.5 draw, 0 loss) a side rated home is
+// expected to get against a side rated away.
+func ExpectedScore(home, away float64) float64 {
+	return 1 / (1 + math.Pow(10, (away-home)/400))
+}
+
+func sideIndex(team string) int {
+	if team == TeamAway {
+		return 1
+	}
+	return 0
+}
+
+// Round1 rounds value to one decimal, the precision ratings are shown with.
+func Round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
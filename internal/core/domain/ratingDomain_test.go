@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var (
+	playerA = uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	playerB = uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	playerC = uuid.MustParse("00000000-0000-0000-0000-00000000000c")
+)
+
+// finishedMatch is a match of home against away with the given score.
+func finishedMatch(homeGoals, awayGoals int, home []uuid.UUID, away []uuid.UUID) Match {
+	match := Match{ID: uuid.New(), HomeGoals: &homeGoals, AwayGoals: &awayGoals}
+	for _, id := range home {
+		match.Players = append(match.Players, MatchPlayer{IDPlay: id, Team: TeamHome})
+	}
+	for _, id := range away {
+		match.Players = append(match.Players, MatchPlayer{IDPlay: id, Team: TeamAway})
+	}
+	return match
+}
+
+func closeTo(got, want float64) bool {
+	return math.Abs(got-want) < 1e-3
+}
+
+func TestRateMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		ratings   map[uuid.UUID]float64
+		match     Match
+		wantDelta float64 // of the home side
+	}{
+		{"win by one", nil, finishedMatch(1, 0, []uuid.UUID{playerA}, []uuid.UUID{playerB}), 21.012},
+		{"draw between equals", nil, finishedMatch(2, 2, []uuid.UUID{playerA}, []uuid.UUID{playerB}), 0},
+		{"loss by two", nil, finishedMatch(0, 2, []uuid.UUID{playerA}, []uuid.UUID{playerB}), -24.823},
+		{"favourite held to a draw", map[uuid.UUID]float64{playerA: 1600}, finishedMatch(1, 1, []uuid.UUID{playerA}, []uuid.UUID{playerB}), -4.482},
+		{"sides rated on their mean", map[uuid.UUID]float64{playerA: 1700, playerC: 1300}, finishedMatch(1, 1, []uuid.UUID{playerA, playerC}, []uuid.UUID{playerB}), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := RateMatch(tt.ratings, tt.match)
+			if len(changes) != len(tt.match.Players) {
+				t.Fatalf("got %d changes, want %d", len(changes), len(tt.match.Players))
+			}
+			for i, change := range changes {
+				player := tt.match.Players[i]
+				before, ok := tt.ratings[player.IDPlay]
+				if !ok {
+					before = InitialRating
+				}
+				want := before + tt.wantDelta
+				if player.Team == TeamAway {
+					want = before - tt.wantDelta
+				}
+				if change.IDPlay != player.IDPlay || change.IDMatch != tt.match.ID || change.Before != before || !closeTo(change.After, want) {
+					t.Errorf("change = %+v, want %v -> %.3f", change, before, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRateMatchWithoutOpponents(t *testing.T) {
+	if changes := RateMatch(nil, finishedMatch(3, 0, []uuid.UUID{playerA, playerB}, nil)); changes != nil {
+		t.Fatalf("changes = %+v, want none", changes)
+	}
+}
+
+func TestComputeRatings(t *testing.T) {
+	first := finishedMatch(1, 0, []uuid.UUID{playerA}, []uuid.UUID{playerB})
+	unfinished := Match{ID: uuid.New(), Players: first.Players}
+	second := finishedMatch(0, 0, []uuid.UUID{playerA}, []uuid.UUID{playerC})
+
+	changes := ComputeRatings([]Match{first, unfinished, second})
+	if len(changes) != 4 {
+		t.Fatalf("got %d changes, want 4: %+v", len(changes), changes)
+	}
+	// The second match starts from the first one's result; C is new.
+	if changes[2].IDPlay != playerA || changes[2].Before != changes[0].After {
+		t.Errorf("A entered the second match at %v, want %v", changes[2].Before, changes[0].After)
+	}
+	if changes[3].IDPlay != playerC || changes[3].Before != InitialRating {
+		t.Errorf("C entered the second match at %v, want %v", changes[3].Before, InitialRating)
+	}
+	// A was the favourite, so a draw costs them points.
+	if changes[2].After >= changes[2].Before {
+		t.Errorf("A drew as favourite and went from %v to %v", changes[2].Before, changes[2].After)
+	}
+}
+
+func TestBlendRatings(t *testing.T) {
+	overall := func(value int) *int { return &value }
+
+	tests := []struct {
+		name          string
+		weight        float64
+		ratings       []Rating
+		wantRating    []float64
+		wantStrengths []float64
+	}{
+		{
+			name:          "overall only",
+			weight:        0,
+			ratings:       []Rating{{Rating: 1600, Overall: overall(70)}, {Rating: 1500, Overall: overall(80)}},
+			wantRating:    []float64{80, 75},
+			wantStrengths: []float64{70, 80},
+		},
+		{
+			name:          "half and half",
+			weight:        0.5,
+			ratings:       []Rating{{Rating: 1600, Overall: overall(70)}, {Rating: 1500, Overall: overall(80)}},
+			wantRating:    []float64{80, 75},
+			wantStrengths: []float64{75, 77.5},
+		},
+		{
+			name:          "rating only",
+			weight:        1,
+			ratings:       []Rating{{Rating: 1600, Overall: overall(70)}, {Rating: 1500, Overall: overall(80)}},
+			wantRating:    []float64{80, 75},
+			wantStrengths: []float64{80, 75},
+		},
+		{
+			name:          "player without overall",
+			weight:        0,
+			ratings:       []Rating{{Rating: 1500, Overall: overall(70)}, {Rating: 1551}},
+			wantRating:    []float64{70, 72.6},
+			wantStrengths: []float64{70, 72.6},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			BlendRatings(tt.ratings, tt.weight)
+			for i, rating := range tt.ratings {
+				if rating.RatingOverall == nil || *rating.RatingOverall != tt.wantRating[i] {
+					t.Errorf("ratings[%d].RatingOverall = %v, want %v", i, rating.RatingOverall, tt.wantRating[i])
+				}
+				if rating.Strength == nil || *rating.Strength != tt.wantStrengths[i] {
+					t.Errorf("ratings[%d].Strength = %v, want %v", i, rating.Strength, tt.wantStrengths[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBlendRatingsWithoutOveralls(t *testing.T) {
+	ratings := []Rating{{Rating: 1600}, {Rating: InitialRating}}
+	BlendRatings(ratings, 0.5)
+	for _, rating := range ratings {
+		if rating.RatingOverall != nil || rating.Strength != nil {
+			t.Errorf("rating = %+v, want no blend", rating)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RecalculateJobRunning  = "running"
+	RecalculateJobFinished = "finished"
+	// RecalculateJobCancelled is a job stopped by shutdown before it
+	// published every card.
+	RecalculateJobCancelled = "cancelled"
+)
+
+type RecalculateJob struct {
+	ID         uuid.UUID  `json:"id"`
+	IDPosition int        `json:"id_position"`
+	Status     string     `json:"status"`
+	Total      int        `json:"total"`
+	Published  int        `json:"published"`
+	Failed     int        `json:"failed"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
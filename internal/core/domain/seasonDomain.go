@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DateLayout is how season dates travel in JSON and in queries.
+const DateLayout = "2006-01-02"
+
+type Season struct {
+	ID       int        `json:"id"`
+	Name     string     `json:"name" binding:"required,notblank,max=50"`
+	StartsOn string     `json:"starts_on" binding:"required,datetime=2006-01-02"`
+	EndsOn   string     `json:"ends_on" binding:"required,datetime=2006-01-02"`
+	ClosedAt *time.Time `json:"closed_at"`
+	Version  int        `json:"version"`
+}
+
+// Closed reports whether the season was closed and no longer takes matches.
+func (s Season) Closed() bool {
+	return s.ClosedAt != nil
+}
+
+// Contains reports whether t falls on a day of the season.
+func (s Season) Contains(t time.Time) bool {
+	day := t.Format(DateLayout)
+	return day >= s.StartsOn && day <= s.EndsOn
+}
+
+// ValidateMatches checks that s, with its dates about to change, still holds
+// every match already registered in it: it cannot start after the day of its
+// first match nor end before the day of its last.
+func (s Season) ValidateMatches(matches []Match) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	first, last := matches[0].PlayedAt, matches[0].PlayedAt
+	for _, match := range matches[1:] {
+		if match.PlayedAt.Before(first) {
+			first = match.PlayedAt
+		}
+		if match.PlayedAt.After(last) {
+			last = match.PlayedAt
+		}
+	}
+
+	var errs ValidationErrors
+	if day := first.Format(DateLayout); day < s.StartsOn {
+		errs.add("starts_on", "must not be after %s, the day of the season's first match", day)
+	}
+	if day := last.Format(DateLayout); day > s.EndsOn {
+		errs.add("ends_on", "must not be before %s, the day of the season's last match", day)
+	}
+	return errs.orNil()
+}
+
+type CreateSeasonRequest struct {
+	Name     string `json:"name" binding:"required,notblank,max=50"`
+	StartsOn string `json:"starts_on" binding:"required,datetime=2006-01-02"`
+	EndsOn   string `json:"ends_on" binding:"required,datetime=2006-01-02"`
+}
+
+// SeasonPlayerStats aggregates the finished matches of one player in a
+// season. AverageTeamOverall is the mean overall of the teams they played
+// for, nil when none of their team mates had an overall.
+type SeasonPlayerStats struct {
+	IDPlay             uuid.UUID `json:"id_play"`
+	Name               string    `json:"name"`
+	Games              int       `json:"games"`
+	Wins               int       `json:"wins"`
+	Draws              int       `json:"draws"`
+	Losses             int       `json:"losses"`
+	Goals              int       `json:"goals"`
+	Assists            int       `json:"assists"`
+	AverageTeamOverall *float64  `json:"average_team_overall"`
+}
+
+// SeasonSnapshot is a player's card and overall frozen when the season was
+// closed.
+type SeasonSnapshot struct {
+	IDSeason int       `json:"id_season"`
+	IDPlay   uuid.UUID `json:"id_play"`
+	Name     string    `json:"name"`
+	PAC      int       `json:"pac"`
+	SHO      int       `json:"sho"`
+	PAS      int       `json:"pas"`
+	DRI      int       `json:"dri"`
+	DEF      int       `json:"def"`
+	PHY      int       `json:"phy"`
+	Overall  *int      `json:"overall"`
+}
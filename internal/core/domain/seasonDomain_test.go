@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSeasonValidateMatches(t *testing.T) {
+	playedOn := func(days ...string) []Match {
+		matches := make([]Match, 0, len(days))
+		for _, day := range days {
+			playedAt, err := time.Parse(DateLayout, day)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matches = append(matches, Match{PlayedAt: playedAt.Add(20 * time.Hour)})
+		}
+		return matches
+	}
+
+	tests := []struct {
+		name    string
+		season  Season
+		matches []Match
+		want    []FieldError
+	}{
+		{"no matches", Season{StartsOn: "2026-06-01", EndsOn: "2026-06-30"}, nil, nil},
+		{"matches inside", Season{StartsOn: "2026-03-01", EndsOn: "2026-09-30"}, playedOn("2026-05-10", "2026-03-02", "2026-08-01"), nil},
+		{"matches on the first and last days", Season{StartsOn: "2026-03-02", EndsOn: "2026-08-01"}, playedOn("2026-05-10", "2026-03-02", "2026-08-01"), nil},
+		{
+			"starts after the first match",
+			Season{StartsOn: "2026-04-01", EndsOn: "2026-09-30"},
+			playedOn("2026-05-10", "2026-03-02", "2026-03-20"),
+			[]FieldError{{Field: "starts_on", Message: "must not be after 2026-03-02, the day of the season's first match"}},
+		},
+		{
+			"ends before the last match",
+			Season{StartsOn: "2026-03-01", EndsOn: "2026-07-31"},
+			playedOn("2026-08-01", "2026-03-02", "2026-08-15"),
+			[]FieldError{{Field: "ends_on", Message: "must not be before 2026-08-15, the day of the season's last match"}},
+		},
+		{
+			"both",
+			Season{StartsOn: "2026-04-01", EndsOn: "2026-04-30"},
+			playedOn("2026-05-10", "2026-03-02"),
+			[]FieldError{
+				{Field: "starts_on", Message: "must not be after 2026-03-02, the day of the season's first match"},
+				{Field: "ends_on", Message: "must not be before 2026-05-10, the day of the season's last match"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.season.ValidateMatches(tt.matches)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("ValidateMatches = %v, want nil", err)
+				}
+				return
+			}
+			errs, ok := err.(ValidationErrors)
+			if !ok || !reflect.DeepEqual([]FieldError(errs), tt.want) {
+				t.Errorf("ValidateMatches = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Leaderboard metrics.
+const (
+	MetricGoals   = "goals"
+	MetricAssists = "assists"
+	MetricWins    = "wins"
+	MetricWinRate = "win_rate"
+	MetricOverall = "overall"
+)
+
+// LeaderboardQuery selects a ranking. Season 0 covers every season; for the
+// overall metric a season means the overalls frozen when it was closed.
+type LeaderboardQuery struct {
+	Metric   string `form:"metric" json:"metric" binding:"required,oneof=goals assists wins win_rate overall"`
+	Season   int    `form:"season" json:"season" binding:"min=0"`
+	MinGames int    `form:"min_games" json:"min_games" binding:"min=0"`
+	Limit    int    `form:"limit" json:"limit" binding:"min=0,max=100"`
+}
+
+type LeaderboardEntry struct {
+	Rank   int       `json:"rank"`
+	IDPlay uuid.UUID `json:"id_play"`
+	Name   string    `json:"name"`
+	Games  int       `json:"games"`
+	Value  float64   `json:"value"`
+}
+
+// PlayerStatsQuery narrows a player's statistics to one season (0 is every
+// season) and, when Teammates is set, compares only those team mates.
+type PlayerStatsQuery struct {
+	Season    int      `form:"season" json:"season" binding:"min=0"`
+	Teammates []string `form:"teammate" json:"teammate" binding:"dive,uuid"`
+}
+
+// PlayerStats sums up a player's finished matches. Rates and averages are
+// nil when there is no match to compute them from.
+type PlayerStats struct {
+	IDPlay            uuid.UUID       `json:"id_play"`
+	Games             int             `json:"games"`
+	Wins              int             `json:"wins"`
+	Draws             int             `json:"draws"`
+	Losses            int             `json:"losses"`
+	WinRate           *float64        `json:"win_rate"`
+	Goals             int             `json:"goals"`
+	Assists           int             `json:"assists"`
+	GoalsPerMatch     *float64        `json:"goals_per_match"`
+	AssistsPerMatch   *float64        `json:"assists_per_match"`
+	CurrentStreak     Streak          `json:"current_streak"`
+	LongestWinStreak  int             `json:"longest_win_streak"`
+	LongestLossStreak int             `json:"longest_loss_streak"`
+	Teammates         []TeammateStats `json:"teammates"`
+}
+
+// Match outcomes, from the point of view of one player.
+const (
+	OutcomeWin  = "win"
+	OutcomeDraw = "draw"
+	OutcomeLoss = "loss"
+)
+
+// Streak is a run of consecutive matches with the same outcome. Outcome is
+// empty when there are no matches.
+type Streak struct {
+	Outcome string `json:"outcome"`
+	Length  int    `json:"length"`
+}
+
+// TeammateStats compares a player's results with and without a team mate on
+// their side.
+type TeammateStats struct {
+	IDPlay         uuid.UUID `json:"id_play"`
+	Name           string    `json:"name"`
+	GamesWith      int       `json:"games_with"`
+	WinRateWith    *float64  `json:"win_rate_with"`
+	GamesWithout   int       `json:"games_without"`
+	WinRateWithout *float64  `json:"win_rate_without"`
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors collects every invalid field of a request so the client
+// can fix them all at once.
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, fieldError := range v {
+		messages = append(messages, fieldError.Field+": "+fieldError.Message)
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+func (v ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func (v *ValidationErrors) add(field, format string, args ...any) {
+	*v = append(*v, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v ValidationErrors) orNil() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
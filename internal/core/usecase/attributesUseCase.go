@@ -3,200 +3,130 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"rachao/infra/logging"
 	"rachao/infra/repositories"
 	"rachao/internal/core/domain"
-	"strconv"
 
-	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 type AttributesUseCase struct {
 	AttributesRepository repositories.AttributeRepositoryInterface
 	PositionRepository   repositories.PositionRepositoryInterface
+	RecalculateUseCase   *RecalculateUseCase
 	db                   *sql.DB
 	logger               *zap.Logger
 }
 
-func NewAttributesUseCase(attributesRepository repositories.AttributeRepositoryInterface, positionRepository repositories.PositionRepositoryInterface, db *sql.DB, logger *zap.Logger) *AttributesUseCase {
+func NewAttributesUseCase(attributesRepository repositories.AttributeRepositoryInterface, positionRepository repositories.PositionRepositoryInterface, recalculateUseCase *RecalculateUseCase, db *sql.DB, logger *zap.Logger) *AttributesUseCase {
 	return &AttributesUseCase{
 		AttributesRepository: attributesRepository,
 		PositionRepository:   positionRepository,
+		RecalculateUseCase:   recalculateUseCase,
 		db:                   db,
 		logger:               logger,
 	}
 }
 
-func (uc AttributesUseCase) Create(ctx context.Context, c *gin.Context) {
-	attributes := domain.AttributesRequest{}
-	if err := c.ShouldBindJSON(&attributes); err != nil {
-		uc.logger.Error("Error binding JSON", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid request data"})
-		return
+func (uc AttributesUseCase) Create(ctx context.Context, attributes domain.AttributesRequest) (int, error) {
+	if err := attributes.Validate(); err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Invalid attributes", zap.Error(err))
+		return 0, err
 	}
 
 	err := uc.validateExists(ctx, attributes.IDPosition)
 	if err != nil {
-		uc.logger.Error("Error fetching attributes by ID", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Bad Request"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error validating attributes position", zap.Error(err))
+		return 0, err
 	}
-	attributesID, err := uc.AttributesRepository.Create(attributes)
+	attributesID, err := uc.AttributesRepository.Create(ctx, attributes)
 	if err != nil {
-		uc.logger.Error("Error creating attributes", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error creating attributes", zap.Error(err))
+		return 0, err
 	}
-
-	c.JSON(201, gin.H{"data": attributesID})
-
+	return attributesID, nil
 }
 
-func (uc AttributesUseCase) GetAll(ctx context.Context, c *gin.Context) {
-	attributes, err := uc.AttributesRepository.GetAll()
+func (uc AttributesUseCase) GetAll(ctx context.Context) ([]domain.Attributes, error) {
+	attributes, err := uc.AttributesRepository.GetAll(ctx)
 	if err != nil {
-		uc.logger.Error("Error fetching attributes", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching attributes", zap.Error(err))
+		return nil, err
 	}
-	if attributes == nil {
-		c.JSON(200, gin.H{"message": "No data found"})
-		return
-	}
-	c.JSON(200, gin.H{"data": attributes})
+	return attributes, nil
 }
 
-func (uc AttributesUseCase) GetByIDPosition(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	idInt, err := strconv.Atoi(id)
+func (uc AttributesUseCase) GetByIDPosition(ctx context.Context, idPosition int) (domain.Attributes, error) {
+	attributes, err := uc.AttributesRepository.GetByIDPosition(ctx, idPosition)
 	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching attributes by position", zap.Error(err))
+		return domain.Attributes{}, err
 	}
-	attributes, err := uc.AttributesRepository.GetByIDPosition(idInt)
-	if err != nil {
-		uc.logger.Error("Error fetching attributes by ID", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	if attributes == (domain.Attributes{}) {
-		c.JSON(404, gin.H{"message": "Attributes not found"})
-		return
-	}
-	c.JSON(200, gin.H{"data": attributes})
+	return attributes, nil
 }
 
-func (uc AttributesUseCase) GetByIDAttributes(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	idInt, err := strconv.Atoi(id)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
-	}
-	attributes, err := uc.AttributesRepository.GetByIDAttributes(idInt)
+func (uc AttributesUseCase) GetByIDAttributes(ctx context.Context, id int) (domain.Attributes, error) {
+	attributes, err := uc.AttributesRepository.GetByIDAttributes(ctx, id)
 	if err != nil {
-		uc.logger.Error("Error fetching attributes by ID", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	if attributes == (domain.Attributes{}) {
-		c.JSON(404, gin.H{"message": "Attributes not found"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching attributes by ID", zap.Error(err))
+		return domain.Attributes{}, err
 	}
-	c.JSON(200, gin.H{"data": attributes})
+	return attributes, nil
 }
 
-func (uc AttributesUseCase) Update(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	idInt, err := strconv.Atoi(id)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
-	}
-	attributes := domain.AttributesRequest{}
-	if err := c.ShouldBindJSON(&attributes); err != nil {
-		uc.logger.Error("Error binding JSON", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid request data"})
-		return
+// Update stores the new weights if the record is still at version (0 skips
+// the check) and recalculates the overalls of their position. The position
+// the weights moved away from is left as it is: it has no weights any more,
+// so there is nothing to recalculate it with. It returns the new version
+// with the started job, or nil if the job failed to start: that is only
+// logged, since the weights are already saved.
+func (uc AttributesUseCase) Update(ctx context.Context, id int, attributes domain.AttributesRequest, version int) (int, *domain.RecalculateJob, error) {
+	if err := attributes.Validate(); err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Invalid attributes", zap.Error(err))
+		return 0, nil, err
 	}
 
-	err = uc.validateAttributesExists(ctx, idInt)
+	err := uc.validateExists(ctx, attributes.IDPosition)
 	if err != nil {
-		uc.logger.Error("Error fetching attributes by ID", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Bad Request"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error validating attributes position", zap.Error(err))
+		return 0, nil, err
 	}
 
-	err = uc.AttributesRepository.Update(attributes, idInt)
+	newVersion, err := uc.AttributesRepository.Update(ctx, attributes, id, version)
 	if err != nil {
-		uc.logger.Error("Error updating attributes", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error updating attributes", zap.Error(err))
+		return 0, nil, err
 	}
-	c.JSON(200, gin.H{"message": "Attributes updated successfully"})
-}
 
-func (uc AttributesUseCase) Delete(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	idInt, err := strconv.Atoi(id)
+	job, err := uc.RecalculateUseCase.StartJob(ctx, attributes.IDPosition)
 	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error starting overall recalculation", zap.Int("position", attributes.IDPosition), zap.Error(err))
+		return newVersion, nil, nil
 	}
-	err = uc.validateAttributesExists(ctx, idInt)
-	if err != nil {
-		uc.logger.Error("Error fetching attributes by ID", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Bad Request"})
-		return
-	}
-	err = uc.AttributesRepository.Delete(idInt)
-	if err != nil {
-		uc.logger.Error("Error deleting attributes", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	c.JSON(200, gin.H{"message": "Attributes deleted successfully"})
+	return newVersion, &job, nil
 }
 
-func (uc AttributesUseCase) validateAttributesExists(_ context.Context, id int) error {
-	var attributes domain.Attributes
-
-	attributes, err := uc.AttributesRepository.GetByIDAttributes(id)
+func (uc AttributesUseCase) Delete(ctx context.Context, id int) error {
+	err := uc.AttributesRepository.Delete(ctx, id)
 	if err != nil {
-		uc.logger.Error("Error fetching attributes by ID", zap.Error(err))
+		logging.FromContext(ctx, uc.logger).Error("Error deleting attributes", zap.Error(err))
 		return err
 	}
-	if attributes == (domain.Attributes{}) {
-		return sql.ErrNoRows
-	}
-
 	return nil
 }
 
-func (uc AttributesUseCase) validateExists(_ context.Context, id int) error {
-	var attributes domain.Attributes
-
-	possition, err := uc.PositionRepository.GetByID(id)
+// validateExists checks that the position exists. A second set of weights for
+// the same position is rejected by the database as a Conflict.
+func (uc AttributesUseCase) validateExists(ctx context.Context, id int) error {
+	_, err := uc.PositionRepository.GetByID(ctx, id)
 	if err != nil {
-		uc.logger.Error("Error fetching position by ID", zap.Error(err))
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewFieldError("id_position", "position %d does not exist", id)
+		}
+		logging.FromContext(ctx, uc.logger).Error("Error fetching position by ID", zap.Error(err))
 		return err
 	}
-	if possition == (domain.Position{}) {
-		return sql.ErrNoRows
-	}
-
-	attributes, err = uc.AttributesRepository.GetByIDPosition(id)
-	if err != nil {
-		uc.logger.Error("Error fetching attributes by ID", zap.Error(err))
-		return err
-	}
-	if attributes != (domain.Attributes{}) {
-		return sql.ErrNoRows
-	}
 
 	return nil
 }
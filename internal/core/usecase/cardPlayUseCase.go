@@ -3,10 +3,10 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"rachao/infra/logging"
 	"rachao/infra/repositories"
 	"rachao/internal/core/domain"
 
-	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -25,52 +25,29 @@ func NewCardPlayUseCase(cardPlayRepository repositories.CardPlayRepositoryInterf
 	}
 }
 
-func (uc CardPlayUseCase) GetAll(ctx context.Context, c *gin.Context) {
-	cardPlays, err := uc.CardPlayRepository.GetAll()
+func (uc CardPlayUseCase) GetAll(ctx context.Context) ([]domain.CardPlay, error) {
+	cardPlays, err := uc.CardPlayRepository.GetAll(ctx)
 	if err != nil {
-		uc.logger.Error("Error fetching card plays", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching card plays", zap.Error(err))
+		return nil, err
 	}
-	if cardPlays == nil {
-		c.JSON(200, gin.H{"message": "No data found"})
-		return
-	}
-	c.JSON(200, gin.H{"data": cardPlays})
+	return cardPlays, nil
 }
 
-func (uc CardPlayUseCase) GetAllByInactive(ctx context.Context, c *gin.Context) {
-	cardPlays, err := uc.CardPlayRepository.GetAllByInactive()
+func (uc CardPlayUseCase) GetAllByInactive(ctx context.Context) ([]domain.CardPlay, error) {
+	cardPlays, err := uc.CardPlayRepository.GetAllByInactive(ctx)
 	if err != nil {
-		uc.logger.Error("Error fetching inactive card plays", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	if cardPlays == nil {
-		c.JSON(200, gin.H{"message": "No data found"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching inactive card plays", zap.Error(err))
+		return nil, err
 	}
-	c.JSON(200, gin.H{"data": cardPlays})
+	return cardPlays, nil
 }
 
-func (uc CardPlayUseCase) GetByID(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	uuid, err := uuid.Parse(id)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
-	cardPlay, err := uc.CardPlayRepository.GetByID(uuid)
+func (uc CardPlayUseCase) GetByID(ctx context.Context, id uuid.UUID) (domain.CardPlay, error) {
+	cardPlay, err := uc.CardPlayRepository.GetByID(ctx, id)
 	if err != nil {
-		uc.logger.Error("Error fetching card play by ID", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	if cardPlay == (domain.CardPlay{}) {
-		c.JSON(404, gin.H{"message": "Card play not found"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching card play by ID", zap.Error(err))
+		return domain.CardPlay{}, err
 	}
-	c.JSON(200, gin.H{"data": cardPlay})
+	return cardPlay, nil
 }
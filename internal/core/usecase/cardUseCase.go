@@ -4,11 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"rachao/infra/logging"
 	"rachao/infra/messaging"
 	"rachao/infra/repositories"
 	"rachao/internal/core/domain"
 
-	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -18,6 +18,7 @@ type CardUseCase struct {
 	CardPlayRepository  repositories.CardPlayRepositoryInterface
 	AttributeRepository repositories.AttributeRepositoryInterface
 	MessagingUseCase    messaging.MessagePublisherInterface
+	exchange            string
 	db                  *sql.DB
 	logger              *zap.Logger
 }
@@ -27,6 +28,7 @@ func NewCardUseCase(
 	cardPlayRepository repositories.CardPlayRepositoryInterface,
 	attributeRepository repositories.AttributeRepositoryInterface,
 	messagingUseCase messaging.MessagePublisherInterface,
+	exchange string,
 	db *sql.DB,
 	logger *zap.Logger,
 
@@ -36,132 +38,101 @@ func NewCardUseCase(
 		CardPlayRepository:  cardPlayRepository,
 		AttributeRepository: attributeRepository,
 		MessagingUseCase:    messagingUseCase,
+		exchange:            exchange,
 		db:                  db,
 		logger:              logger,
 	}
 }
 
-func (uc CardUseCase) GetByID(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	uuid, err := uuid.Parse(id)
+func (uc CardUseCase) GetByID(ctx context.Context, idPlay uuid.UUID) (domain.Card, error) {
+	card, err := uc.CardRepository.GetByIDPlay(ctx, idPlay)
 	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching card", zap.Error(err))
+		return domain.Card{}, err
 	}
-	card, err := uc.CardRepository.GetByIDPlay(uuid)
-	if err != nil {
-		uc.logger.Error("Error fetching card", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	c.JSON(200, gin.H{"data": card})
+	return card, nil
 }
 
-func (uc CardUseCase) Create(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	uuid, err := uuid.Parse(id)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
-	}
-	var card domain.CardRequest
-	if err := c.ShouldBindJSON(&card); err != nil {
-		uc.logger.Error("Invalid request body", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid request body"})
-		return
+func (uc CardUseCase) Create(ctx context.Context, idPlay uuid.UUID, card domain.CardRequest) (uuid.UUID, error) {
+	if err := card.Validate(); err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Invalid card", zap.Error(err))
+		return uuid.Nil, err
 	}
 
-	cardID, err := uc.CardRepository.Create(uuid, card)
+	cardID, err := uc.CardRepository.Create(ctx, idPlay, card)
 	if err != nil {
-		uc.logger.Error("Error creating card", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error creating card", zap.Error(err))
+		return uuid.Nil, err
 	}
 
-	err = uc.calculatorOverall(cardID)
+	err = uc.calculatorOverall(ctx, cardID)
 	if err != nil {
-		uc.logger.Error("Error calculating overall", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error calculating overall", zap.Error(err))
+		return uuid.Nil, err
 	}
 
-	c.JSON(201, gin.H{"id": cardID})
+	return cardID, nil
 }
 
-func (uc CardUseCase) Update(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	uuid, err := uuid.Parse(id)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
+// Update replaces the stats on the card of a play and requests a new overall
+// for it; the overall is computed asynchronously by the consumer.
+func (uc CardUseCase) Update(ctx context.Context, idPlay uuid.UUID, card domain.CardRequest, version int) (int, error) {
+	if err := card.Validate(); err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Invalid card", zap.Error(err))
+		return 0, err
 	}
 
-	var card domain.CardRequest
-	if err := c.ShouldBindJSON(&card); err != nil {
-		uc.logger.Error("Invalid request body", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid request body"})
-		return
-	}
-	if card == (domain.CardRequest{}) {
-		uc.logger.Error("Request body is empty")
-		c.JSON(400, gin.H{"error": "Request body cannot be null"})
-		return
-	}
-	IDCard, err := uc.CardRepository.Update(uuid, card)
+	IDCard, newVersion, err := uc.CardRepository.Update(ctx, idPlay, card, version)
 	if err != nil {
-		uc.logger.Error("Error updating card", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error updating card", zap.Error(err))
+		return 0, err
 	}
 
-	err = uc.calculatorOverall(IDCard)
+	err = uc.calculatorOverall(ctx, IDCard)
 	if err != nil {
-		uc.logger.Error("Error calculating overall", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error calculating overall", zap.Error(err))
+		return 0, err
 	}
 
-	c.JSON(200, gin.H{"message": "Card updated successfully"})
+	return newVersion, nil
 }
 
-func (uc CardUseCase) calculatorOverall(id uuid.UUID) error {
-	card, err := uc.CardRepository.GetByID(id)
+func (uc CardUseCase) calculatorOverall(ctx context.Context, id uuid.UUID) error {
+	card, err := uc.CardRepository.GetByID(ctx, id)
 	if err != nil {
-		uc.logger.Error("Error fetching card", zap.Error(err))
+		logging.FromContext(ctx, uc.logger).Error("Error fetching card", zap.Error(err))
 		return err
 	}
 
-	cardPlay, err := uc.CardPlayRepository.GetByID(card.IDPlay)
+	cardPlay, err := uc.CardPlayRepository.GetByID(ctx, card.IDPlay)
 	if err != nil {
-		uc.logger.Error("Error fetching card play", zap.Error(err))
+		logging.FromContext(ctx, uc.logger).Error("Error fetching card play", zap.Error(err))
 		return err
 	}
 
-	attributes, err := uc.AttributeRepository.GetByIDPosition(cardPlay.IDPosition)
+	attributes, err := uc.AttributeRepository.GetByIDPosition(ctx, cardPlay.IDPosition)
 	if err != nil {
-		uc.logger.Error("Error fetching attributes", zap.Error(err))
+		logging.FromContext(ctx, uc.logger).Error("Error fetching attributes", zap.Error(err))
 		return err
 
 	}
 
 	overallRequest := domain.OverallBodyRequest{
+		Version:    domain.OverallMessageVersion,
 		Card:       card,
 		Attributes: attributes,
 	}
 
 	overallRequestBytes, err := json.Marshal(overallRequest)
 	if err != nil {
-		uc.logger.Error("Error serializing overall request", zap.Error(err))
+		logging.FromContext(ctx, uc.logger).Error("Error serializing overall request", zap.Error(err))
 		return err
 	}
 
 	cardMessaging := "card." + card.IDPlay.String()
-	err = uc.MessagingUseCase.Publish("rachao", cardMessaging, overallRequestBytes)
+	err = uc.MessagingUseCase.Publish(ctx, uc.exchange, cardMessaging, overallRequestBytes)
 	if err != nil {
-		uc.logger.Error("Error publishing: ", zap.Error(err))
+		logging.FromContext(ctx, uc.logger).Error("Error publishing: ", zap.Error(err))
 		return err
 
 	}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"rachao/infra/logging"
+	"rachao/infra/repositories"
+	"rachao/internal/core/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type FormationUseCase struct {
+	FormationRepository repositories.FormationRepositoryInterface
+	ModalityRepository  repositories.ModalityRepositoryInterface
+	RatingUseCase       *RatingUseCase
+	db                  *sql.DB
+	logger              *zap.Logger
+}
+
+func NewFormationUseCase(formationRepository repositories.FormationRepositoryInterface, modalityRepository repositories.ModalityRepositoryInterface, ratingUseCase *RatingUseCase, db *sql.DB, logger *zap.Logger) *FormationUseCase {
+	return &FormationUseCase{
+		FormationRepository: formationRepository,
+		ModalityRepository:  modalityRepository,
+		RatingUseCase:       ratingUseCase,
+		db:                  db,
+		logger:              logger,
+	}
+}
+
+func (uc FormationUseCase) GetByModality(ctx context.Context, idModality int) ([]domain.Formation, error) {
+	if _, err := uc.ModalityRepository.GetByID(ctx, idModality); err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching modality by ID", zap.Error(err))
+		return nil, err
+	}
+	formations, err := uc.FormationRepository.GetByModality(ctx, idModality)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching formations", zap.Error(err))
+		return nil, err
+	}
+	return formations, nil
+}
+
+func (uc FormationUseCase) GetByID(ctx context.Context, id int) (domain.Formation, error) {
+	formation, err := uc.FormationRepository.GetByID(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching formation by ID", zap.Error(err))
+		return domain.Formation{}, err
+	}
+	return formation, nil
+}
+
+// Create stores a formation whose lines add up to the modality's players per
+// side.
+func (uc FormationUseCase) Create(ctx context.Context, formation domain.CreateFormationRequest) (int, error) {
+	modality, err := uc.ModalityRepository.GetByID(ctx, formation.IDModality)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return 0, domain.NewFieldError("id_modality", "modality %d does not exist", formation.IDModality)
+		}
+		logging.FromContext(ctx, uc.logger).Error("Error fetching modality by ID", zap.Error(err))
+		return 0, err
+	}
+	if size := (domain.Formation{Lines: formation.Lines}).Size(); size != modality.Amount_play {
+		return 0, domain.NewFieldError("lines", "must add up to %d players, not %d", modality.Amount_play, size)
+	}
+	id, err := uc.FormationRepository.Create(ctx, formation)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error creating formation", zap.Error(err))
+		return 0, err
+	}
+	return id, nil
+}
+
+func (uc FormationUseCase) Delete(ctx context.Context, id int) error {
+	if err := uc.FormationRepository.Delete(ctx, id); err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error deleting formation", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Lineup drafts the players into two sides of the formation, balanced on
+// their strength. Every player must be active and have an overall, and there
+// must be exactly enough of them for both sides.
+func (uc FormationUseCase) Lineup(ctx context.Context, id int, request domain.LineupRequest) (domain.Draft, error) {
+	formation, err := uc.GetByID(ctx, id)
+	if err != nil {
+		return domain.Draft{}, err
+	}
+	if want := 2 * formation.Size(); len(request.Players) != want {
+		return domain.Draft{}, domain.NewFieldError("players", "must have %d players for two sides of %s", want, formation.Name)
+	}
+
+	players, err := uc.FormationRepository.GetPlayers(ctx, request.Players)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching lineup players", zap.Error(err))
+		return domain.Draft{}, err
+	}
+	found := make(map[uuid.UUID]domain.LineupPlayer, len(players))
+	for _, player := range players {
+		found[player.IDPlay] = player
+	}
+	var errs domain.ValidationErrors
+	for _, idPlay := range request.Players {
+		player, ok := found[idPlay]
+		switch {
+		case !ok:
+			errs = append(errs, domain.FieldError{Field: "players", Message: fmt.Sprintf("player %s does not exist or is inactive", idPlay)})
+		case player.Overall == nil:
+			errs = append(errs, domain.FieldError{Field: "players", Message: fmt.Sprintf("player %s has no overall", idPlay)})
+		}
+	}
+	if len(errs) > 0 {
+		return domain.Draft{}, errs
+	}
+
+	strengths, err := uc.RatingUseCase.Strengths(ctx)
+	if err != nil {
+		return domain.Draft{}, err
+	}
+	for i, player := range players {
+		strength, ok := strengths[player.IDPlay]
+		if !ok {
+			strength = float64(*player.Overall)
+		}
+		players[i].Strength = strength
+	}
+	return domain.BuildDraft(formation, players), nil
+}
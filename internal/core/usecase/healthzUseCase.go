@@ -1,23 +1,86 @@
 package usecase
 
 import (
-	"net/http"
+	"context"
+	"database/sql"
+	"rachao/infra/logging"
+	"rachao/infra/messaging"
 	"rachao/internal/core/domain"
+	"time"
 
-	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
-type HealthzUseCase struct{}
+const healthCheckTimeout = 2 * time.Second
 
-func (h *HealthzUseCase) Healthz() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		healthzEntity := &domain.Healthz{
-			Message: "system is up",
-			Version: 1.0,
-			Status:  "ok",
-		}
+type HealthzUseCase struct {
+	Messaging messaging.MessagePublisherInterface
+	Version   string
+	Commit    string
+	db        *sql.DB
+	logger    *zap.Logger
+}
+
+func NewHealthzUseCase(messaging messaging.MessagePublisherInterface, version, commit string, db *sql.DB, logger *zap.Logger) *HealthzUseCase {
+	return &HealthzUseCase{
+		Messaging: messaging,
+		Version:   version,
+		Commit:    commit,
+		db:        db,
+		logger:    logger,
+	}
+}
 
-		c.JSON(http.StatusOK, healthzEntity)
+// Live only tells the process is running; it never touches dependencies so
+// a broker or database outage does not get the instance restarted.
+func (h *HealthzUseCase) Live(ctx context.Context) domain.Healthz {
+	return domain.Healthz{
+		Message: "system is up",
+		Status:  domain.HealthStatusOK,
+		Version: h.Version,
+		Commit:  h.Commit,
+	}
+}
 
+// Ready checks every dependency the instance needs to serve traffic and
+// returns false when any of them is down.
+func (h *HealthzUseCase) Ready(ctx context.Context) (domain.Healthz, bool) {
+	checks := map[string]domain.HealthCheck{
+		"database": h.check(func() error {
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			return h.db.PingContext(ctx)
+		}),
+		"rabbitmq": h.check(h.Messaging.Check),
+	}
+
+	healthz := domain.Healthz{
+		Message: "system is ready",
+		Status:  domain.HealthStatusOK,
+		Version: h.Version,
+		Commit:  h.Commit,
+		Checks:  checks,
+	}
+	for name, check := range checks {
+		if check.Status != domain.HealthStatusOK {
+			logging.FromContext(ctx, h.logger).Warn("Readiness check failed", zap.String("dependency", name), zap.String("error", check.Error))
+			healthz.Message = "system is not ready"
+			healthz.Status = domain.HealthStatusDown
+		}
+	}
+	return healthz, healthz.Status == domain.HealthStatusOK
+}
+
+func (h *HealthzUseCase) check(fn func() error) domain.HealthCheck {
+	start := time.Now()
+	err := fn()
+	check := domain.HealthCheck{
+		Status:    domain.HealthStatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		check.Status = domain.HealthStatusDown
+		check.Error = err.Error()
 	}
+	return check
 }
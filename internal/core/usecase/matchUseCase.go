@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"rachao/infra/logging"
+	"rachao/infra/repositories"
+	"rachao/internal/core/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type MatchUseCase struct {
+	MatchRepository    repositories.MatchRepositoryInterface
+	SeasonRepository   repositories.SeasonRepositoryInterface
+	ModalityRepository repositories.ModalityRepositoryInterface
+	RatingUseCase      *RatingUseCase
+	db                 *sql.DB
+	logger             *zap.Logger
+}
+
+func NewMatchUseCase(matchRepository repositories.MatchRepositoryInterface, seasonRepository repositories.SeasonRepositoryInterface, modalityRepository repositories.ModalityRepositoryInterface, ratingUseCase *RatingUseCase, db *sql.DB, logger *zap.Logger) *MatchUseCase {
+	return &MatchUseCase{
+		MatchRepository:    matchRepository,
+		SeasonRepository:   seasonRepository,
+		ModalityRepository: modalityRepository,
+		RatingUseCase:      ratingUseCase,
+		db:                 db,
+		logger:             logger,
+	}
+}
+
+func (uc MatchUseCase) GetByID(ctx context.Context, id uuid.UUID) (domain.Match, error) {
+	match, err := uc.MatchRepository.GetByID(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching match by ID", zap.Error(err))
+		return domain.Match{}, err
+	}
+	return match, nil
+}
+
+// Create registers a match in an open season, on one of its days, with each
+// side fielding the modality's players per side and no player on both.
+func (uc MatchUseCase) Create(ctx context.Context, match domain.CreateMatchRequest) (uuid.UUID, error) {
+	season, err := uc.openSeason(ctx, match.IDSeason)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	modality, err := uc.ModalityRepository.GetByID(ctx, match.IDModality)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return uuid.Nil, domain.NewFieldError("id_modality", "modality %d does not exist", match.IDModality)
+		}
+		logging.FromContext(ctx, uc.logger).Error("Error fetching modality by ID", zap.Error(err))
+		return uuid.Nil, err
+	}
+	var errs domain.ValidationErrors
+	if !season.Contains(match.PlayedAt) {
+		errs = append(errs, domain.FieldError{Field: "played_at", Message: fmt.Sprintf("must fall between %s and %s", season.StartsOn, season.EndsOn)})
+	}
+	if len(match.Home) != modality.Amount_play {
+		errs = append(errs, domain.FieldError{Field: "home", Message: fmt.Sprintf("must have %d players, not %d", modality.Amount_play, len(match.Home))})
+	}
+	if len(match.Away) != modality.Amount_play {
+		errs = append(errs, domain.FieldError{Field: "away", Message: fmt.Sprintf("must have %d players, not %d", modality.Amount_play, len(match.Away))})
+	}
+	home := make(map[uuid.UUID]bool, len(match.Home))
+	for _, id := range match.Home {
+		home[id] = true
+	}
+	for _, id := range match.Away {
+		if home[id] {
+			errs = append(errs, domain.FieldError{Field: "away", Message: fmt.Sprintf("player %s is also in home", id)})
+		}
+	}
+	if len(errs) > 0 {
+		return uuid.Nil, errs
+	}
+
+	id, err := uc.MatchRepository.Create(ctx, match)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error creating match", zap.Error(err))
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+// RecordResult stores the score and the players' goals and assists and
+// returns the new version of the match. A side's goals cannot add up to more
+// than its score, nor its assists to more than its goals. The match is then
+// rated.
+func (uc MatchUseCase) RecordResult(ctx context.Context, id uuid.UUID, result domain.MatchResultRequest, version int) (int, error) {
+	match, err := uc.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := uc.openSeason(ctx, match.IDSeason); err != nil {
+		return 0, err
+	}
+
+	var errs domain.ValidationErrors
+	goals := map[string]int{}
+	assists := map[string]int{}
+	seen := make(map[uuid.UUID]bool, len(result.Players))
+	for _, player := range result.Players {
+		team := match.Team(player.IDPlay)
+		switch {
+		case team == "":
+			errs = append(errs, domain.FieldError{Field: "players", Message: fmt.Sprintf("player %s did not play this match", player.IDPlay)})
+		case seen[player.IDPlay]:
+			errs = append(errs, domain.FieldError{Field: "players", Message: fmt.Sprintf("player %s is listed twice", player.IDPlay)})
+		}
+		seen[player.IDPlay] = true
+		goals[team] += player.Goals
+		assists[team] += player.Assists
+	}
+	score := map[string]int{domain.TeamHome: *result.HomeGoals, domain.TeamAway: *result.AwayGoals}
+	for _, team := range []string{domain.TeamHome, domain.TeamAway} {
+		if goals[team] > score[team] {
+			errs = append(errs, domain.FieldError{Field: "players", Message: fmt.Sprintf("%s players scored more than the %s score", team, team)})
+		}
+		if assists[team] > goals[team] {
+			errs = append(errs, domain.FieldError{Field: "players", Message: fmt.Sprintf("%s players have more assists than goals", team)})
+		}
+	}
+	if len(errs) > 0 {
+		return 0, errs
+	}
+
+	newVersion, err := uc.MatchRepository.RecordResult(ctx, id, result, version)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error recording match result", zap.Error(err))
+		return 0, err
+	}
+	match.HomeGoals, match.AwayGoals = result.HomeGoals, result.AwayGoals
+	if err := uc.RatingUseCase.Record(ctx, match); err != nil {
+		uc.staleRatings(ctx, err)
+	}
+	return newVersion, nil
+}
+
+func (uc MatchUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	match, err := uc.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := uc.openSeason(ctx, match.IDSeason); err != nil {
+		return err
+	}
+	if err := uc.MatchRepository.Delete(ctx, id); err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error deleting match", zap.Error(err))
+		return err
+	}
+	if match.Finished() {
+		if _, err := uc.RatingUseCase.Rebuild(ctx); err != nil {
+			uc.staleRatings(ctx, err)
+		}
+	}
+	return nil
+}
+
+// staleRatings reports ratings left behind by a change of results. The
+// change itself stands; POST /admin/rating/rebuild catches the ratings up.
+func (uc MatchUseCase) staleRatings(ctx context.Context, err error) {
+	logging.FromContext(ctx, uc.logger).Warn("Ratings are out of date until the next rebuild", zap.Error(err))
+}
+
+// openSeason returns the season a match belongs to, reporting a missing one
+// as an invalid id_season and a closed one as a conflict, since closed
+// seasons are frozen.
+func (uc MatchUseCase) openSeason(ctx context.Context, id int) (domain.Season, error) {
+	season, err := uc.SeasonRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return season, domain.NewFieldError("id_season", "season %d does not exist", id)
+		}
+		logging.FromContext(ctx, uc.logger).Error("Error fetching season", zap.Error(err))
+		return season, err
+	}
+	if season.Closed() {
+		return season, domain.NewConflictError("season %d is closed", id)
+	}
+	return season, nil
+}
@@ -1,7 +1,9 @@
 package usecase
 
 import (
+	"context"
 	"encoding/json"
+	"rachao/infra/logging"
 	"rachao/infra/messaging"
 	"rachao/internal/core/domain"
 
@@ -10,29 +12,34 @@ import (
 
 type MessagingUseCase struct {
 	messagePublisherInterface messaging.MessagePublisherInterface
+	exchange                  string
 	logger                    *zap.Logger
 }
 
-func NewMessagingaUseCase(messagePublisherInterface messaging.MessagePublisherInterface, logger *zap.Logger) *MessagingUseCase {
+// NewMessagingaUseCase builds the publisher of overall requests to exchange
+// (MESSAGING_CHANNEL).
+func NewMessagingaUseCase(messagePublisherInterface messaging.MessagePublisherInterface, exchange string, logger *zap.Logger) *MessagingUseCase {
 	return &MessagingUseCase{
 		messagePublisherInterface: messagePublisherInterface,
+		exchange:                  exchange,
 		logger:                    logger,
 	}
 }
 
-func (uc *MessagingUseCase) Publish(overallRequest domain.OverallBodyRequest) error {
+func (uc *MessagingUseCase) Publish(ctx context.Context, overallRequest domain.OverallBodyRequest) error {
+	overallRequest.Version = domain.OverallMessageVersion
 	serializedRequest, err := json.Marshal(overallRequest)
 	if err != nil {
-		uc.logger.Error("Failed to serialize overallRequest", zap.Error(err))
+		logging.FromContext(ctx, uc.logger).Error("Failed to serialize overallRequest", zap.Error(err))
 		return err
 	}
 
 	card := "card." + overallRequest.Card.IDPlay.String()
-	err = uc.messagePublisherInterface.Publish("rachao", card, []byte(serializedRequest))
+	err = uc.messagePublisherInterface.Publish(ctx, uc.exchange, card, []byte(serializedRequest))
 	if err != nil {
-		uc.logger.Error("Failed to publish message", zap.Error(err))
+		logging.FromContext(ctx, uc.logger).Error("Failed to publish message", zap.Error(err))
 		return err
 	}
-	uc.logger.Info("Message published successfully", zap.String("card", card), zap.String("message", string(serializedRequest)))
+	logging.FromContext(ctx, uc.logger).Info("Message published successfully", zap.String("card", card), zap.String("message", string(serializedRequest)))
 	return nil
 }
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"rachao/infra/metrics"
+	"rachao/infra/repositories"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// metricsRefreshInterval is how often the business gauges are recomputed;
+// counting on every scrape would put the load on the database instead.
+const metricsRefreshInterval = 30 * time.Second
+
+type MetricsUseCase struct {
+	PlayRepository repositories.PlayRepositoryInterface
+	CardRepository repositories.CardRepositoryInterface
+	logger         *zap.Logger
+}
+
+func NewMetricsUseCase(playRepository repositories.PlayRepositoryInterface, cardRepository repositories.CardRepositoryInterface, logger *zap.Logger) *MetricsUseCase {
+	return &MetricsUseCase{
+		PlayRepository: playRepository,
+		CardRepository: cardRepository,
+		logger:         logger,
+	}
+}
+
+// Start refreshes the business gauges until ctx is done.
+func (uc *MetricsUseCase) Start(ctx context.Context) {
+	ticker := time.NewTicker(metricsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		uc.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *MetricsUseCase) refresh(ctx context.Context) {
+	if active, err := uc.PlayRepository.CountActive(ctx); err != nil {
+		uc.logger.Warn("Error counting active players", zap.Error(err))
+	} else {
+		metrics.ActivePlayers.Set(float64(active))
+	}
+
+	if pending, err := uc.CardRepository.CountWithoutOverall(ctx); err != nil {
+		uc.logger.Warn("Error counting cards without overall", zap.Error(err))
+	} else {
+		metrics.CardsWithoutOverall.Set(float64(pending))
+	}
+}
@@ -3,160 +3,125 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"rachao/infra/logging"
 	"rachao/infra/repositories"
 	"rachao/internal/core/domain"
-	"strconv"
 
-	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 type ModalityUseCase struct {
-	ModalityRepository repositories.ModalityRepositoryInterface
-	db                 *sql.DB
-	logger             *zap.Logger
+	ModalityRepository  repositories.ModalityRepositoryInterface
+	FormationRepository repositories.FormationRepositoryInterface
+	PositionRepository  repositories.PositionRepositoryInterface
+	db                  *sql.DB
+	logger              *zap.Logger
 }
 
-func NewModalityUseCase(modalityRepository repositories.ModalityRepositoryInterface, db *sql.DB, logger *zap.Logger) *ModalityUseCase {
+func NewModalityUseCase(modalityRepository repositories.ModalityRepositoryInterface, formationRepository repositories.FormationRepositoryInterface, positionRepository repositories.PositionRepositoryInterface, db *sql.DB, logger *zap.Logger) *ModalityUseCase {
 	return &ModalityUseCase{
-		ModalityRepository: modalityRepository,
-		db:                 db,
-		logger:             logger,
+		ModalityRepository:  modalityRepository,
+		FormationRepository: formationRepository,
+		PositionRepository:  positionRepository,
+		db:                  db,
+		logger:              logger,
 	}
 }
 
-func (uc ModalityUseCase) GetAll(ctx context.Context, c *gin.Context) {
-	modalities, err := uc.ModalityRepository.GetAll()
+func (uc ModalityUseCase) GetAll(ctx context.Context) ([]domain.Modality, error) {
+	modalities, err := uc.ModalityRepository.GetAll(ctx)
 	if err != nil {
-		uc.logger.Error("Error fetching modalities", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching modalities", zap.Error(err))
+		return nil, err
 	}
-	if modalities == nil {
-		c.JSON(200, gin.H{"message": "No data found"})
-		return
-	}
-	c.JSON(200, gin.H{"data": modalities})
+	return modalities, nil
 }
 
-func (uc ModalityUseCase) GetAllByInactive(ctx context.Context, c *gin.Context) {
-	modalities, err := uc.ModalityRepository.GetAllByInactive()
+func (uc ModalityUseCase) GetAllByInactive(ctx context.Context) ([]domain.Modality, error) {
+	modalities, err := uc.ModalityRepository.GetAllByInactive(ctx)
 	if err != nil {
-		uc.logger.Error("Error fetching inactive modalities", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	if modalities == nil {
-		c.JSON(200, gin.H{"message": "No data found"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching inactive modalities", zap.Error(err))
+		return nil, err
 	}
-	c.JSON(200, gin.H{"data": modalities})
+	return modalities, nil
 }
 
-func (uc ModalityUseCase) GetByID(ctx context.Context, c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.Atoi(idParam)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
-	modality, err := uc.ModalityRepository.GetByID(id)
+func (uc ModalityUseCase) GetByID(ctx context.Context, id int) (domain.Modality, error) {
+	modality, err := uc.ModalityRepository.GetByID(ctx, id)
 	if err != nil {
-		uc.logger.Error("Error fetching modality by ID", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching modality by ID", zap.Error(err))
+		return domain.Modality{}, err
 	}
-	if modality == (domain.Modality{}) {
-		c.JSON(404, gin.H{"message": "No data found"})
-		return
-	}
-	c.JSON(200, gin.H{"data": modality})
+	return modality, nil
 }
 
-func (uc ModalityUseCase) Create(ctx context.Context, c *gin.Context) {
-	var modality domain.Modality
-	if err := c.ShouldBindJSON(&modality); err != nil {
-		uc.logger.Error("Invalid request body", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid request body"})
-		return
-	}
-
-	createRequest := domain.CreateModalityRequest{
-		Name:        modality.Name,
-		Amount_play: modality.Amount_play,
-		Active:      modality.Active,
-	}
-
-	idModality, err := uc.ModalityRepository.Create(createRequest)
+// Create stores a modality with the default formation of its size, when
+// there is one and its positions exist; see domain.DefaultFormation. Other
+// modalities start without formations and get theirs through the formation
+// use case. A default formation that fails to be stored is logged and left
+// out, since the modality is already saved.
+func (uc ModalityUseCase) Create(ctx context.Context, modality domain.CreateModalityRequest) (int, error) {
+	idModality, err := uc.ModalityRepository.Create(ctx, modality)
 	if err != nil {
-		uc.logger.Error("Error creating modality", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error creating modality", zap.Error(err))
+		return 0, err
 	}
-	c.JSON(201, gin.H{"message": "Modality created successfully", "id": idModality})
-}
 
-func (uc ModalityUseCase) Update(ctx context.Context, c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.Atoi(idParam)
+	positions, err := uc.PositionRepository.GetAll(ctx)
 	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching positions for the default formation", zap.Error(err))
+		return idModality, nil
 	}
-
-	var modality domain.Modality
-	if err := c.ShouldBindJSON(&modality); err != nil {
-		uc.logger.Error("Invalid request body", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid request body"})
-		return
+	formation, ok := domain.DefaultFormation(idModality, modality.Amount_play, positions)
+	if !ok {
+		logging.FromContext(ctx, uc.logger).Info("No default formation for modality", zap.Int("modality", idModality), zap.Int("amount_play", modality.Amount_play))
+		return idModality, nil
 	}
+	if _, err := uc.FormationRepository.Create(ctx, formation); err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error creating default formation", zap.Int("modality", idModality), zap.Error(err))
+	}
+	return idModality, nil
+}
 
+// Update saves a modality under the given ID, whatever ID the body carries.
+// Its formations must fill the new amount_play, so changing the number of
+// players is refused while it has formations of another size.
+func (uc ModalityUseCase) Update(ctx context.Context, id int, modality domain.Modality, version int) (int, error) {
 	modality.ID = id
 
-	err = uc.ModalityRepository.Update(modality)
+	formations, err := uc.FormationRepository.GetByModality(ctx, id)
 	if err != nil {
-		uc.logger.Error("Error updating modality", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching formations", zap.Error(err))
+		return 0, err
 	}
-	c.JSON(200, gin.H{"message": "Modality updated successfully"})
-}
-
-func (uc ModalityUseCase) Inactive(ctx context.Context, c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.Atoi(idParam)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
+	for _, formation := range formations {
+		if size := formation.Size(); size != modality.Amount_play {
+			return 0, domain.NewConflictError("formation %q of modality %d has %d players; delete it before changing amount_play to %d", formation.Name, id, size, modality.Amount_play)
+		}
 	}
 
-	err = uc.ModalityRepository.Inactive(id)
+	newVersion, err := uc.ModalityRepository.Update(ctx, modality, version)
 	if err != nil {
-		uc.logger.Error("Error inactivating modality", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error updating modality", zap.Error(err))
+		return 0, err
 	}
-	c.JSON(200, gin.H{"message": "Modality inactivated successfully"})
+	return newVersion, nil
 }
 
-func (uc ModalityUseCase) Active(ctx context.Context, c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.Atoi(idParam)
+func (uc ModalityUseCase) Inactive(ctx context.Context, id int) error {
+	err := uc.ModalityRepository.Inactive(ctx, id)
 	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error inactivating modality", zap.Error(err))
+		return err
 	}
+	return nil
+}
 
-	err = uc.ModalityRepository.Active(id)
+func (uc ModalityUseCase) Active(ctx context.Context, id int) error {
+	err := uc.ModalityRepository.Active(ctx, id)
 	if err != nil {
-		uc.logger.Error("Error activating modality", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error activating modality", zap.Error(err))
+		return err
 	}
-	c.JSON(200, gin.H{"message": "Modality activated successfully"})
+	return nil
 }
@@ -3,11 +3,10 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"rachao/infra/logging"
 	"rachao/infra/repositories"
 	"rachao/internal/core/domain"
-	"strconv"
 
-	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
@@ -25,80 +24,39 @@ func NewNationUseCase(nationRepository repositories.NationRepositoryInterface, d
 	}
 }
 
-func (uc NationUseCase) GetAll(ctx context.Context, c *gin.Context) {
-	nations, err := uc.NationRepository.GetAll()
+func (uc NationUseCase) GetAll(ctx context.Context) ([]domain.Nation, error) {
+	nations, err := uc.NationRepository.GetAll(ctx)
 	if err != nil {
-		uc.logger.Error("Error fetching nations", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching nations", zap.Error(err))
+		return nil, err
 	}
-	if nations == nil {
-		c.JSON(200, gin.H{"message": "No data found"})
-		return
-	}
-	c.JSON(200, gin.H{"data": nations})
+	return nations, nil
 }
 
-func (uc NationUseCase) GetByID(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	nationID, err := strconv.Atoi(id)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
-	nation, err := uc.NationRepository.GetByID(nationID)
+func (uc NationUseCase) GetByID(ctx context.Context, id int) (domain.Nation, error) {
+	nation, err := uc.NationRepository.GetByID(ctx, id)
 	if err != nil {
-		uc.logger.Error("Error fetching nation by ID", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	if nation == (domain.Nation{}) {
-		c.JSON(404, gin.H{"message": "Nation not found"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching nation by ID", zap.Error(err))
+		return domain.Nation{}, err
 	}
-	c.JSON(200, gin.H{"data": nation})
+	return nation, nil
 }
 
-func (uc NationUseCase) Create(ctx context.Context, c *gin.Context) {
-	var nation domain.CreateNationRequest
-	if err := c.ShouldBindJSON(&nation); err != nil {
-		uc.logger.Error("Invalid request body", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid request body"})
-		return
-	}
-
-	id, err := uc.NationRepository.Create(nation)
+func (uc NationUseCase) Create(ctx context.Context, nation domain.CreateNationRequest) (int, error) {
+	id, err := uc.NationRepository.Create(ctx, nation)
 	if err != nil {
-		uc.logger.Error("Error creating nation", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error creating nation", zap.Error(err))
+		return 0, err
 	}
-	c.JSON(201, gin.H{"message": "Nation created successfully", "id": id})
+	return id, nil
 }
 
-func (uc NationUseCase) Update(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	nationID, err := strconv.Atoi(id)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
-	var nation domain.Nation
-	if err := c.ShouldBindJSON(&nation); err != nil {
-		uc.logger.Error("Invalid request body", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid request body"})
-		return
-	}
-
-	err = uc.NationRepository.Update(nationID, nation)
+// Update renames a nation. Plays reference it by ID, so they follow along.
+func (uc NationUseCase) Update(ctx context.Context, id int, nation domain.Nation, version int) (int, error) {
+	newVersion, err := uc.NationRepository.Update(ctx, id, nation, version)
 	if err != nil {
-		uc.logger.Error("Error updating nation", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error updating nation", zap.Error(err))
+		return 0, err
 	}
-	c.JSON(200, gin.H{"message": "Nation updated successfully"})
+	return newVersion, nil
 }
@@ -1,8 +1,11 @@
 package usecase
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"rachao/infra/logging"
 	"rachao/infra/messaging"
 	"rachao/infra/repositories"
 	"rachao/internal/core/domain"
@@ -13,6 +16,7 @@ import (
 type OverallUseCase struct {
 	Messaging         messaging.MessagePublisherInterface
 	OverallRepository repositories.OverallRepositoryInterface
+	PlayRepository    repositories.PlayRepositoryInterface
 	db                *sql.DB
 	Logger            *zap.Logger
 }
@@ -20,6 +24,7 @@ type OverallUseCase struct {
 func NewOverallUseCase(
 	Messaging messaging.MessagePublisherInterface,
 	overallRepository repositories.OverallRepositoryInterface,
+	playRepository repositories.PlayRepositoryInterface,
 	db *sql.DB,
 	logger *zap.Logger,
 
@@ -27,6 +32,7 @@ func NewOverallUseCase(
 	return &OverallUseCase{
 		Messaging:         Messaging,
 		OverallRepository: overallRepository,
+		PlayRepository:    playRepository,
 		db:                db,
 		Logger:            logger,
 	}
@@ -34,18 +40,23 @@ func NewOverallUseCase(
 
 func (uc *OverallUseCase) Start() {
 
-	handler := func(message string) {
+	handler := func(ctx context.Context, message string) error {
+		logger := logging.FromContext(ctx, uc.Logger)
 
-		uc.Logger.Info("Received message", zap.String("message", message))
+		logger.Info("Received message", zap.String("message", message))
 
-		err := uc.overallCreateUpdate(message)
+		err := uc.overallCreateUpdate(ctx, message)
 		if err != nil {
-			uc.Logger.Error("Error processing message", zap.Error(err))
-			return
+			if errors.Is(err, domain.ErrInvalidOverallMessage) {
+				logger.Warn("Rejecting invalid overall message", zap.Error(err))
+				return fmt.Errorf("%w: %w", messaging.ErrRejected, err)
+			}
+			logger.Error("Error processing message", zap.Error(err))
+			return err
 		}
 
-		uc.Logger.Info("Message processed successfully")
-
+		logger.Info("Message processed successfully")
+		return nil
 	}
 
 	err := uc.Messaging.Consumer(handler, "overall")
@@ -56,34 +67,33 @@ func (uc *OverallUseCase) Start() {
 
 }
 
-func (uc *OverallUseCase) overallCreateUpdate(message string) error {
-	var overallRequest domain.OverallRequest
-	err := json.Unmarshal([]byte(message), &overallRequest)
+// Stop stops consuming overall messages once the one in progress is done.
+func (uc *OverallUseCase) Stop(ctx context.Context) error {
+	return uc.Messaging.Stop(ctx)
+}
+
+func (uc *OverallUseCase) overallCreateUpdate(ctx context.Context, message string) error {
+	overallRequest, err := domain.DecodeOverallRequest([]byte(message))
 	if err != nil {
-		uc.Logger.Error("Error unmarshalling message", zap.Error(err))
+		logging.FromContext(ctx, uc.Logger).Error("Error decoding message", zap.Error(err))
 		return err
 	}
 
-	exist, err := uc.OverallRepository.Exists(overallRequest.IDPlay)
-	if err != nil && err == sql.ErrNoRows {
-		uc.Logger.Error("Error checking overall existence", zap.Error(err))
-	}
-	if exist {
-		updateErr := uc.OverallRepository.Update(overallRequest, overallRequest.IDPlay)
-		if updateErr != nil {
-			uc.Logger.Error("Error updating overall", zap.Error(updateErr))
-			return updateErr
+	_, err = uc.PlayRepository.GetByID(ctx, overallRequest.IDPlay)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: unknown play %s", domain.ErrInvalidOverallMessage, overallRequest.IDPlay)
 		}
+		logging.FromContext(ctx, uc.Logger).Error("Error fetching play by ID", zap.Error(err))
+		return err
 	}
 
-	if !exist {
-		_, Err := uc.OverallRepository.Create(overallRequest)
-		if Err != nil {
-			uc.Logger.Error("Error creating overall", zap.Error(Err))
-			return Err
-		}
+	_, err = uc.OverallRepository.Upsert(ctx, overallRequest)
+	if err != nil {
+		logging.FromContext(ctx, uc.Logger).Error("Error saving overall", zap.Error(err))
+		return err
 	}
 
-	uc.Logger.Info("Overall created/updated successfully", zap.String("id", overallRequest.IDPlay.String()))
+	logging.FromContext(ctx, uc.Logger).Info("Overall created/updated successfully", zap.String("id", overallRequest.IDPlay.String()))
 	return nil
 }
@@ -3,182 +3,175 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"rachao/infra/logging"
+	"rachao/infra/messaging"
 	"rachao/infra/repositories"
 	"rachao/internal/core/domain"
+	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 type PlayUseCase struct {
-	PlayRepository repositories.PlayRepositoryInterface
-	db             *sql.DB
-	logger         *zap.Logger
+	PlayRepository     repositories.PlayRepositoryInterface
+	PositionRepository repositories.PositionRepositoryInterface
+	NationRepository   repositories.NationRepositoryInterface
+	Messaging          messaging.MessagePublisherInterface
+	RatingUseCase      *RatingUseCase
+	exchange           string
+	db                 *sql.DB
+	logger             *zap.Logger
 }
 
-func NewPlayUseCase(playRepository repositories.PlayRepositoryInterface, db *sql.DB, logger *zap.Logger) *PlayUseCase {
+// NewPlayUseCase builds the play use case. exchange is the one events are
+// published to (MESSAGING_CHANNEL).
+func NewPlayUseCase(playRepository repositories.PlayRepositoryInterface, positionRepository repositories.PositionRepositoryInterface, nationRepository repositories.NationRepositoryInterface, messaging messaging.MessagePublisherInterface, ratingUseCase *RatingUseCase, exchange string, db *sql.DB, logger *zap.Logger) *PlayUseCase {
 	return &PlayUseCase{
-		PlayRepository: playRepository,
-		db:             db,
-		logger:         logger,
+		PlayRepository:     playRepository,
+		PositionRepository: positionRepository,
+		NationRepository:   nationRepository,
+		Messaging:          messaging,
+		RatingUseCase:      ratingUseCase,
+		exchange:           exchange,
+		db:                 db,
+		logger:             logger,
 	}
 }
 
-func (uc PlayUseCase) GetAll(ctx context.Context, c *gin.Context) {
-	plays, err := uc.PlayRepository.GetAll()
+func (uc PlayUseCase) GetAll(ctx context.Context) ([]domain.Play, error) {
+	plays, err := uc.PlayRepository.GetAll(ctx)
 	if err != nil {
-		uc.logger.Error("Error fetching plays", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching plays", zap.Error(err))
+		return nil, err
 	}
-	if plays == nil {
-		c.JSON(200, gin.H{"message": "No data found"})
-		return
-	}
-	c.JSON(200, gin.H{"data": plays})
+	return plays, nil
 }
 
-func (uc PlayUseCase) GetAllByInactive(ctx context.Context, c *gin.Context) {
-	plays, err := uc.PlayRepository.GetAllByInactive()
+func (uc PlayUseCase) GetAllByInactive(ctx context.Context) ([]domain.Play, error) {
+	plays, err := uc.PlayRepository.GetAllByInactive(ctx)
 	if err != nil {
-		uc.logger.Error("Error fetching inactive plays", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	if plays == nil {
-		c.JSON(200, gin.H{"message": "No data found"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching inactive plays", zap.Error(err))
+		return nil, err
 	}
-	c.JSON(200, gin.H{"data": plays})
+	return plays, nil
 }
 
-func (uc PlayUseCase) GetByID(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	uuid, err := uuid.Parse(id)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
-	}
-	play, err := uc.PlayRepository.GetByID(uuid)
+func (uc PlayUseCase) GetByID(ctx context.Context, id uuid.UUID) (domain.Play, error) {
+	play, err := uc.PlayRepository.GetByID(ctx, id)
 	if err != nil {
-		uc.logger.Error("Error fetching play by ID", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	if play == (domain.Play{}) {
-		c.JSON(404, gin.H{"message": "Play not found"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching play by ID", zap.Error(err))
+		return domain.Play{}, err
 	}
-	c.JSON(200, gin.H{"data": play})
+	return play, nil
 }
 
-func (uc PlayUseCase) Create(ctx context.Context, c *gin.Context) {
-	play := domain.CreatePlayRequest{}
-	if err := c.ShouldBindJSON(&play); err != nil {
-		uc.logger.Error("Invalid request body", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid request body"})
-		return
+func (uc PlayUseCase) Create(ctx context.Context, play domain.CreatePlayRequest) (uuid.UUID, error) {
+	if err := uc.validateReferences(ctx, play.IDPosition, play.IDNation); err != nil {
+		return uuid.Nil, err
 	}
-	playName, err := uc.PlayRepository.GetByName(play.Name)
+	id, err := uc.PlayRepository.Create(ctx, play)
 	if err != nil {
-		uc.logger.Error("Error fetching play by name", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error creating play", zap.Error(err))
+		return uuid.Nil, err
 	}
-	if playName != (domain.Play{}) {
-		c.JSON(400, gin.H{"message": "Play already exists"})
-		return
-	}
-	id, err := uc.PlayRepository.Create(play)
-	if err != nil {
-		uc.logger.Error("Error creating play", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	c.JSON(201, gin.H{"message": "Play created successfully", "id": id})
+	return id, nil
 }
 
-func (uc PlayUseCase) Update(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	uuid, err := uuid.Parse(id)
-	if err != nil {
-		uc.logger.Error("Invalid UUID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid UUID format"})
-		return
-	}
-	play := domain.Play{}
-	if err := c.ShouldBindJSON(&play); err != nil {
-		uc.logger.Error("Invalid request body", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid request body"})
-		return
-	}
-	playID, err := uc.fetchPlayByID(ctx, uuid)
+// Update saves the profile of a play once its position and nation are known
+// to exist, so a typo surfaces as a field error rather than a foreign key
+// violation.
+func (uc PlayUseCase) Update(ctx context.Context, id uuid.UUID, play domain.Play, version int) (int, error) {
+	if err := uc.validateReferences(ctx, play.IDPosition, play.IDNation); err != nil {
+		return 0, err
+	}
+	newVersion, err := uc.PlayRepository.Update(ctx, id, play, version)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error updating play", zap.Error(err))
+		return 0, err
 	}
-	if playID == (domain.Play{}) {
-		c.JSON(404, gin.H{"message": "Play not found"})
-		return
-	}
-	err = uc.PlayRepository.Update(uuid, play)
-	if err != nil {
-		uc.logger.Error("Error updating play", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	c.JSON(200, gin.H{"message": "Play updated successfully"})
+	return newVersion, nil
 }
 
-func (uc PlayUseCase) Delete(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	uuid, err := uuid.Parse(id)
+func (uc PlayUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	err := uc.PlayRepository.Delete(ctx, id)
 	if err != nil {
-		uc.logger.Error("Invalid UUID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid UUID format"})
-		return
-	}
-	playID, err := uc.fetchPlayByID(ctx, uuid)
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error deleting play", zap.Error(err))
+		return err
 	}
+	return nil
+}
 
-	err = uc.PlayRepository.Delete(playID.ID)
+func (uc PlayUseCase) Activate(ctx context.Context, id uuid.UUID) error {
+	err := uc.PlayRepository.Activate(ctx, id)
 	if err != nil {
-		uc.logger.Error("Error deleting play", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error activating play", zap.Error(err))
+		return err
 	}
-	c.JSON(200, gin.H{"message": "Play deleted successfully"})
+	return nil
 }
 
-func (uc PlayUseCase) GetByName(ctx context.Context, c *gin.Context) {
-	name := c.Param("name")
-	play, err := uc.PlayRepository.GetByName(name)
+// Purge permanently removes a play with everything that references it (see
+// PlayRepository.Purge), announces it on "play.deleted" and rebuilds the
+// ratings, since everyone else's were computed through the deleted lines.
+// The deletion stands even if the event cannot be published or the rebuild
+// fails; both failures are logged, and Purge reports whether the ratings
+// were rebuilt so a failed rebuild can be retried.
+func (uc PlayUseCase) Purge(ctx context.Context, id uuid.UUID) (bool, error) {
+	if err := uc.PlayRepository.Purge(ctx, id); err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error purging play", zap.Error(err))
+		return false, err
+	}
+	logging.FromContext(ctx, uc.logger).Info("Play purged", zap.String("id", id.String()))
+
+	body, err := json.Marshal(domain.PlayDeletedEvent{
+		Version:   domain.PlayDeletedMessageVersion,
+		IDPlay:    id,
+		DeletedAt: time.Now().UTC(),
+	})
 	if err != nil {
-		uc.logger.Error("Error fetching play by name", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	if play == (domain.Play{}) {
-		c.JSON(404, gin.H{"message": "Play not found"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Failed to serialize play deleted event", zap.Error(err))
+	} else if err := uc.Messaging.Publish(ctx, uc.exchange, "play.deleted", body); err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Failed to publish play deleted event", zap.String("id", id.String()), zap.Error(err))
 	}
-	c.JSON(200, gin.H{"data": play})
+
+	_, err = uc.RatingUseCase.Rebuild(ctx)
+	return err == nil, nil
 }
 
-func (uc PlayUseCase) fetchPlayByID(_ context.Context, id uuid.UUID) (domain.Play, error) {
-	play, err := uc.PlayRepository.GetByID(id)
+func (uc PlayUseCase) GetByName(ctx context.Context, name string) (domain.Play, error) {
+	play, err := uc.PlayRepository.GetByName(ctx, name)
 	if err != nil {
-		uc.logger.Error("Error fetching play by ID", zap.Error(err))
+		logging.FromContext(ctx, uc.logger).Error("Error fetching play by name", zap.Error(err))
 		return domain.Play{}, err
 	}
-	if play == (domain.Play{}) {
-		uc.logger.Error("Play not found")
-		return play, err
-	}
 	return play, nil
 }
+
+// validateReferences reports, as field errors, a position or nation that does
+// not exist, so the client gets both problems at once instead of a foreign
+// key failure.
+func (uc PlayUseCase) validateReferences(ctx context.Context, idPosition, idNation int) error {
+	var errs domain.ValidationErrors
+	if _, err := uc.PositionRepository.GetByID(ctx, idPosition); err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			logging.FromContext(ctx, uc.logger).Error("Error fetching position", zap.Error(err))
+			return err
+		}
+		errs = append(errs, domain.FieldError{Field: "id_position", Message: fmt.Sprintf("position %d does not exist", idPosition)})
+	}
+	if _, err := uc.NationRepository.GetByID(ctx, idNation); err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			logging.FromContext(ctx, uc.logger).Error("Error fetching nation", zap.Error(err))
+			return err
+		}
+		errs = append(errs, domain.FieldError{Field: "id_nation", Message: fmt.Sprintf("nation %d does not exist", idNation)})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
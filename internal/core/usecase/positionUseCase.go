@@ -3,11 +3,10 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"rachao/infra/logging"
 	"rachao/infra/repositories"
 	"rachao/internal/core/domain"
-	"strconv"
 
-	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
@@ -25,119 +24,50 @@ func NewPositionUseCase(positionRepository repositories.PositionRepositoryInterf
 	}
 }
 
-func (uc PositionUseCase) GetAll(ctx context.Context, c *gin.Context) {
-	positions, err := uc.PositionRepository.GetAll()
+func (uc PositionUseCase) GetAll(ctx context.Context) ([]domain.Position, error) {
+	positions, err := uc.PositionRepository.GetAll(ctx)
 	if err != nil {
-		uc.logger.Error("Error fetching positions", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching positions", zap.Error(err))
+		return nil, err
 	}
-	if positions == nil {
-		c.JSON(200, gin.H{"message": "No data found"})
-		return
-	}
-	c.JSON(200, gin.H{"data": positions})
+	return positions, nil
 }
 
-func (uc PositionUseCase) GetByID(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	idInt, err := strconv.Atoi(id)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
-	}
-	position, err := uc.PositionRepository.GetByID(idInt)
+func (uc PositionUseCase) GetByID(ctx context.Context, id int) (domain.Position, error) {
+	position, err := uc.PositionRepository.GetByID(ctx, id)
 	if err != nil {
-		uc.logger.Error("Error fetching position by ID", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error fetching position by ID", zap.Error(err))
+		return domain.Position{}, err
 	}
-	if position == (domain.Position{}) {
-		c.JSON(404, gin.H{"message": "Position not found"})
-		return
-	}
-	c.JSON(200, gin.H{"data": position})
+	return position, nil
 }
 
-func (uc PositionUseCase) Create(ctx context.Context, c *gin.Context) {
-	positionRequest := domain.CreatePositionRequest{}
-	if err := c.ShouldBindJSON(&positionRequest); err != nil {
-		uc.logger.Error("Invalid request body", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid request body"})
-		return
-	}
-	id, err := uc.PositionRepository.Create(positionRequest)
+func (uc PositionUseCase) Create(ctx context.Context, position domain.CreatePositionRequest) (int, error) {
+	id, err := uc.PositionRepository.Create(ctx, position)
 	if err != nil {
-		uc.logger.Error("Error creating position", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error creating position", zap.Error(err))
+		return 0, err
 	}
-	c.JSON(201, gin.H{"message": "Position created successfully", "id": id})
+	return id, nil
 }
 
-func (uc PositionUseCase) Update(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	idInt, err := strconv.Atoi(id)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
-	}
-	err = uc.validatePositionExists(ctx, idInt)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(404, gin.H{"message": "Position not found"})
-			return
-		}
-	}
-	positionRequest := domain.Position{}
-	if err := c.ShouldBindJSON(&positionRequest); err != nil {
-		uc.logger.Error("Invalid request body", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid request body"})
-		return
-	}
-	positionRequest.ID = idInt
-	err = uc.PositionRepository.Update(idInt, positionRequest)
+// Update renames a position under the given ID. Its attributes and the plays
+// playing it are left untouched.
+func (uc PositionUseCase) Update(ctx context.Context, id int, position domain.Position, version int) (int, error) {
+	position.ID = id
+	newVersion, err := uc.PositionRepository.Update(ctx, id, position, version)
 	if err != nil {
-		uc.logger.Error("Error updating position", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
+		logging.FromContext(ctx, uc.logger).Error("Error updating position", zap.Error(err))
+		return 0, err
 	}
-	c.JSON(200, gin.H{"message": "Position updated successfully"})
+	return newVersion, nil
 }
 
-func (uc PositionUseCase) Delete(ctx context.Context, c *gin.Context) {
-	id := c.Param("id")
-	idInt, err := strconv.Atoi(id)
-	if err != nil {
-		uc.logger.Error("Invalid ID format", zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid ID format"})
-		return
-	}
-	err = uc.validatePositionExists(ctx, idInt)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(404, gin.H{"message": "Position not found"})
-			return
-		}
-	}
-	err = uc.PositionRepository.Delete(idInt)
-	if err != nil {
-		uc.logger.Error("Error deleting position", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal Server Error"})
-		return
-	}
-	c.JSON(200, gin.H{"message": "Position deleted successfully"})
-}
-
-func (uc PositionUseCase) validatePositionExists(_ context.Context, id int) error {
-	position, err := uc.PositionRepository.GetByID(id)
+func (uc PositionUseCase) Delete(ctx context.Context, id int) error {
+	err := uc.PositionRepository.Delete(ctx, id)
 	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error deleting position", zap.Error(err))
 		return err
 	}
-	if position == (domain.Position{}) {
-		return sql.ErrNoRows
-	}
 	return nil
 }
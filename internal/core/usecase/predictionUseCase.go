@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"rachao/infra/logging"
+	"rachao/infra/repositories"
+	"rachao/internal/core/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type PredictionUseCase struct {
+	PredictionRepository repositories.PredictionRepositoryInterface
+	db                   *sql.DB
+	logger               *zap.Logger
+}
+
+func NewPredictionUseCase(predictionRepository repositories.PredictionRepositoryInterface, db *sql.DB, logger *zap.Logger) *PredictionUseCase {
+	return &PredictionUseCase{
+		PredictionRepository: predictionRepository,
+		db:                   db,
+		logger:               logger,
+	}
+}
+
+// Predict forecasts the match from its drafted sides, with a model fitted on
+// every other finished match and its leave-one-out calibration. A finished
+// match is predicted as it would have been before kickoff, from the matches
+// played before it only. A match left without players on a side, after they
+// were purged, cannot be predicted.
+func (uc PredictionUseCase) Predict(ctx context.Context, id uuid.UUID) (domain.Prediction, error) {
+	match, err := uc.PredictionRepository.GetMatch(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching match for prediction", zap.Error(err))
+		return domain.Prediction{}, err
+	}
+	if !match.Drafted() {
+		return domain.Prediction{}, domain.NewFieldError("id", "match needs drafted players on both sides")
+	}
+	history, err := uc.PredictionRepository.GetHistory(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching match history", zap.Error(err))
+		return domain.Prediction{}, err
+	}
+
+	features := match.Features()
+	return domain.Prediction{
+		IDMatch:     id,
+		Outcomes:    domain.FitPredictionModel(history).Predict(features),
+		Features:    features,
+		Calibration: domain.Calibrate(history),
+	}, nil
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"rachao/infra/logging"
+	"rachao/infra/repositories"
+	"rachao/internal/core/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type RatingUseCase struct {
+	RatingRepository repositories.RatingRepositoryInterface
+	PlayRepository   repositories.PlayRepositoryInterface
+	weight           float64
+	db               *sql.DB
+	logger           *zap.Logger
+}
+
+// NewRatingUseCase builds the rating use case. weight is the share of the
+// match rating in a player's strength (RATING_WEIGHT).
+func NewRatingUseCase(ratingRepository repositories.RatingRepositoryInterface, playRepository repositories.PlayRepositoryInterface, weight float64, db *sql.DB, logger *zap.Logger) *RatingUseCase {
+	return &RatingUseCase{
+		RatingRepository: ratingRepository,
+		PlayRepository:   playRepository,
+		weight:           weight,
+		db:               db,
+		logger:           logger,
+	}
+}
+
+// GetAll lists active players by rating, with the rating converted to the
+// overall scale and blended with the overall into their strength.
+func (uc RatingUseCase) GetAll(ctx context.Context) ([]domain.Rating, error) {
+	ratings, err := uc.RatingRepository.GetAll(ctx)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching ratings", zap.Error(err))
+		return nil, err
+	}
+
+	domain.BlendRatings(ratings, uc.weight)
+	for i := range ratings {
+		ratings[i].Rating = domain.Round1(ratings[i].Rating)
+	}
+	return ratings, nil
+}
+
+func (uc RatingUseCase) GetHistory(ctx context.Context, idPlay uuid.UUID) ([]domain.RatingChange, error) {
+	if _, err := uc.PlayRepository.GetByID(ctx, idPlay); err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching play by ID", zap.Error(err))
+		return nil, err
+	}
+	history, err := uc.RatingRepository.GetHistory(ctx, idPlay)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching rating history", zap.Error(err))
+		return nil, err
+	}
+	return history, nil
+}
+
+// Strengths returns the blended strength of every active player with an
+// overall, the figure teams are balanced on.
+func (uc RatingUseCase) Strengths(ctx context.Context) (map[uuid.UUID]float64, error) {
+	ratings, err := uc.RatingRepository.GetAll(ctx)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching ratings", zap.Error(err))
+		return nil, err
+	}
+	domain.BlendRatings(ratings, uc.weight)
+	strengths := make(map[uuid.UUID]float64, len(ratings))
+	for _, rating := range ratings {
+		if rating.Overall != nil && rating.Strength != nil {
+			strengths[rating.IDPlay] = *rating.Strength
+		}
+	}
+	return strengths, nil
+}
+
+// Record rates a match whose result was just stored. When it is the latest
+// finished match and was not rated before, only its own rating changes are
+// appended; a result recorded out of order or corrected changes the later
+// history too, so every match is replayed instead.
+func (uc RatingUseCase) Record(ctx context.Context, match domain.Match) error {
+	appended, err := uc.RatingRepository.Append(ctx, match, domain.RateMatch)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error rating match", zap.String("match", match.ID.String()), zap.Error(err))
+		return err
+	}
+	if appended {
+		return nil
+	}
+	_, err = uc.Rebuild(ctx)
+	return err
+}
+
+// Rebuild replays every finished match to recompute the ratings and their
+// history, and returns how many rating changes were stored.
+func (uc RatingUseCase) Rebuild(ctx context.Context) (int, error) {
+	count, err := uc.RatingRepository.Rebuild(ctx, domain.ComputeRatings)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error rebuilding ratings", zap.Error(err))
+		return 0, err
+	}
+	logging.FromContext(ctx, uc.logger).Info("Ratings rebuilt", zap.Int("changes", count))
+	return count, nil
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"rachao/infra/logging"
+	"sync"
+	"time"
+
+	"rachao/infra/repositories"
+	"rachao/internal/core/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RecalculateJobTTL is how long a finished job stays readable through GetJob.
+const RecalculateJobTTL = time.Hour
+
+// RecalculateUseCase re-publishes overall calculation requests for every card
+// of a position, so overalls follow changes to the position weights.
+type RecalculateUseCase struct {
+	CardPlayRepository  repositories.CardPlayRepositoryInterface
+	AttributeRepository repositories.AttributeRepositoryInterface
+	MessagingUseCase    *MessagingUseCase
+	db                  *sql.DB
+	logger              *zap.Logger
+
+	mu      sync.RWMutex
+	jobs    map[uuid.UUID]*domain.RecalculateJob
+	running sync.WaitGroup
+	stop    context.Context
+	cancel  context.CancelFunc
+}
+
+func NewRecalculateUseCase(
+	cardPlayRepository repositories.CardPlayRepositoryInterface,
+	attributeRepository repositories.AttributeRepositoryInterface,
+	messagingUseCase *MessagingUseCase,
+	db *sql.DB,
+	logger *zap.Logger,
+) *RecalculateUseCase {
+	stop, cancel := context.WithCancel(context.Background())
+	return &RecalculateUseCase{
+		CardPlayRepository:  cardPlayRepository,
+		AttributeRepository: attributeRepository,
+		MessagingUseCase:    messagingUseCase,
+		db:                  db,
+		logger:              logger,
+		jobs:                make(map[uuid.UUID]*domain.RecalculateJob),
+		stop:                stop,
+		cancel:              cancel,
+	}
+}
+
+func (uc *RecalculateUseCase) GetJob(ctx context.Context, id uuid.UUID) (domain.RecalculateJob, error) {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+
+	job, ok := uc.jobs[id]
+	if !ok || expired(job) {
+		return domain.RecalculateJob{}, domain.NewNotFoundError("recalculation job %s not found", id)
+	}
+	return *job, nil
+}
+
+// StartJob loads the cards of the position and publishes their calculation
+// requests in the background. The returned job is a snapshot; progress is
+// read back through GetJob.
+func (uc *RecalculateUseCase) StartJob(ctx context.Context, idPosition int) (domain.RecalculateJob, error) {
+	attributes, err := uc.AttributeRepository.GetByIDPosition(ctx, idPosition)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching attributes", zap.Error(err))
+		return domain.RecalculateJob{}, err
+	}
+
+	cardPlays, err := uc.CardPlayRepository.GetByIDPosition(ctx, idPosition)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching card plays by position", zap.Error(err))
+		return domain.RecalculateJob{}, err
+	}
+
+	job := &domain.RecalculateJob{
+		ID:         uuid.New(),
+		IDPosition: idPosition,
+		Status:     domain.RecalculateJobRunning,
+		Total:      len(cardPlays),
+		StartedAt:  time.Now(),
+	}
+
+	uc.mu.Lock()
+	for id, old := range uc.jobs {
+		if expired(old) {
+			delete(uc.jobs, id)
+		}
+	}
+	uc.jobs[job.ID] = job
+	snapshot := *job
+	uc.mu.Unlock()
+
+	logging.FromContext(ctx, uc.logger).Info("Starting overall recalculation", zap.String("job", job.ID.String()), zap.Int("position", idPosition), zap.Int("total", job.Total))
+	// The job outlives the request; keep its trace but take its cancellation
+	// from Stop.
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	unlink := context.AfterFunc(uc.stop, cancel)
+	uc.running.Add(1)
+	go func() {
+		defer uc.running.Done()
+		defer unlink()
+		defer cancel()
+		uc.run(runCtx, job, cardPlays, attributes)
+	}()
+
+	return snapshot, nil
+}
+
+func (uc *RecalculateUseCase) run(ctx context.Context, job *domain.RecalculateJob, cardPlays []domain.CardPlay, attributes domain.Attributes) {
+	status := domain.RecalculateJobFinished
+	for _, cardPlay := range cardPlays {
+		if ctx.Err() != nil {
+			status = domain.RecalculateJobCancelled
+			break
+		}
+		err := uc.MessagingUseCase.Publish(ctx, domain.OverallBodyRequest{
+			Card:       cardPlay.Card,
+			Attributes: attributes,
+		})
+
+		uc.mu.Lock()
+		if err != nil {
+			job.Failed++
+		} else {
+			job.Published++
+		}
+		uc.mu.Unlock()
+	}
+
+	finishedAt := time.Now()
+	uc.mu.Lock()
+	job.Status = status
+	job.FinishedAt = &finishedAt
+	uc.mu.Unlock()
+
+	logging.FromContext(ctx, uc.logger).Info("Overall recalculation finished",
+		zap.String("job", job.ID.String()),
+		zap.String("status", status),
+		zap.Int("published", job.Published),
+		zap.Int("failed", job.Failed),
+	)
+}
+
+// Stop cancels the running jobs and waits, until ctx is done, for them to
+// stop publishing. It must be called before the broker connection closes.
+func (uc *RecalculateUseCase) Stop(ctx context.Context) error {
+	uc.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		uc.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// expired reports whether a finished job is past RecalculateJobTTL. Callers
+// hold uc.mu.
+func expired(job *domain.RecalculateJob) bool {
+	return job.FinishedAt != nil && time.Since(*job.FinishedAt) > RecalculateJobTTL
+}
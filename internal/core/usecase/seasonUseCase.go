@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"rachao/infra/logging"
+	"rachao/infra/repositories"
+	"rachao/internal/core/domain"
+
+	"go.uber.org/zap"
+)
+
+type SeasonUseCase struct {
+	SeasonRepository repositories.SeasonRepositoryInterface
+	MatchRepository  repositories.MatchRepositoryInterface
+	db               *sql.DB
+	logger           *zap.Logger
+}
+
+func NewSeasonUseCase(seasonRepository repositories.SeasonRepositoryInterface, matchRepository repositories.MatchRepositoryInterface, db *sql.DB, logger *zap.Logger) *SeasonUseCase {
+	return &SeasonUseCase{
+		SeasonRepository: seasonRepository,
+		MatchRepository:  matchRepository,
+		db:               db,
+		logger:           logger,
+	}
+}
+
+func (uc SeasonUseCase) GetAll(ctx context.Context) ([]domain.Season, error) {
+	seasons, err := uc.SeasonRepository.GetAll(ctx)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching seasons", zap.Error(err))
+		return nil, err
+	}
+	return seasons, nil
+}
+
+func (uc SeasonUseCase) GetByID(ctx context.Context, id int) (domain.Season, error) {
+	season, err := uc.SeasonRepository.GetByID(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching season by ID", zap.Error(err))
+		return domain.Season{}, err
+	}
+	return season, nil
+}
+
+func (uc SeasonUseCase) Create(ctx context.Context, season domain.CreateSeasonRequest) (int, error) {
+	if err := validateSeasonDates(season.StartsOn, season.EndsOn); err != nil {
+		return 0, err
+	}
+	id, err := uc.SeasonRepository.Create(ctx, season)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error creating season", zap.Error(err))
+		return 0, err
+	}
+	return id, nil
+}
+
+// Update changes the name or dates of a season that is still open; a closed
+// season is frozen together with its snapshots. The dates must still cover
+// every match registered in the season.
+func (uc SeasonUseCase) Update(ctx context.Context, id int, season domain.Season, version int) (int, error) {
+	if err := validateSeasonDates(season.StartsOn, season.EndsOn); err != nil {
+		return 0, err
+	}
+	current, err := uc.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if current.Closed() {
+		return 0, domain.NewConflictError("season %d is closed", id)
+	}
+	matches, err := uc.MatchRepository.GetBySeason(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching season matches", zap.Error(err))
+		return 0, err
+	}
+	if err := season.ValidateMatches(matches); err != nil {
+		return 0, err
+	}
+	newVersion, err := uc.SeasonRepository.Update(ctx, id, season, version)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error updating season", zap.Error(err))
+		return 0, err
+	}
+	return newVersion, nil
+}
+
+// Close ends the season and snapshots every active player's card and
+// overall, returning the season's new version.
+func (uc SeasonUseCase) Close(ctx context.Context, id int) (int, error) {
+	newVersion, err := uc.SeasonRepository.Close(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error closing season", zap.Error(err))
+		return 0, err
+	}
+	logging.FromContext(ctx, uc.logger).Info("Season closed", zap.Int("id", id))
+	return newVersion, nil
+}
+
+func (uc SeasonUseCase) GetSnapshots(ctx context.Context, id int) ([]domain.SeasonSnapshot, error) {
+	if _, err := uc.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	snapshots, err := uc.SeasonRepository.GetSnapshots(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching season snapshots", zap.Error(err))
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func (uc SeasonUseCase) GetStats(ctx context.Context, id int) ([]domain.SeasonPlayerStats, error) {
+	if _, err := uc.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	stats, err := uc.SeasonRepository.GetStats(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching season stats", zap.Error(err))
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (uc SeasonUseCase) GetMatches(ctx context.Context, id int) ([]domain.Match, error) {
+	if _, err := uc.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	matches, err := uc.MatchRepository.GetBySeason(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching season matches", zap.Error(err))
+		return nil, err
+	}
+	return matches, nil
+}
+
+// validateSeasonDates expects dates already in domain.DateLayout, which
+// compare correctly as strings.
+func validateSeasonDates(startsOn, endsOn string) error {
+	if endsOn < startsOn {
+		return domain.NewFieldError("ends_on", "must not be before starts_on")
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"rachao/infra/logging"
+	"rachao/infra/repositories"
+	"rachao/internal/core/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// defaultLeaderboardLimit is how many players a leaderboard lists when the
+// caller does not say.
+const defaultLeaderboardLimit = 10
+
+type StatsUseCase struct {
+	StatsRepository  repositories.StatsRepositoryInterface
+	PlayRepository   repositories.PlayRepositoryInterface
+	SeasonRepository repositories.SeasonRepositoryInterface
+	db               *sql.DB
+	logger           *zap.Logger
+}
+
+func NewStatsUseCase(statsRepository repositories.StatsRepositoryInterface, playRepository repositories.PlayRepositoryInterface, seasonRepository repositories.SeasonRepositoryInterface, db *sql.DB, logger *zap.Logger) *StatsUseCase {
+	return &StatsUseCase{
+		StatsRepository:  statsRepository,
+		PlayRepository:   playRepository,
+		SeasonRepository: seasonRepository,
+		db:               db,
+		logger:           logger,
+	}
+}
+
+func (uc StatsUseCase) Leaderboard(ctx context.Context, query domain.LeaderboardQuery) ([]domain.LeaderboardEntry, error) {
+	if err := uc.validateSeason(ctx, query.Season); err != nil {
+		return nil, err
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultLeaderboardLimit
+	}
+	entries, err := uc.StatsRepository.Leaderboard(ctx, query)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error computing leaderboard", zap.String("metric", query.Metric), zap.Error(err))
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (uc StatsUseCase) PlayerStats(ctx context.Context, id uuid.UUID, query domain.PlayerStatsQuery) (domain.PlayerStats, error) {
+	if _, err := uc.PlayRepository.GetByID(ctx, id); err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error fetching play by ID", zap.Error(err))
+		return domain.PlayerStats{}, err
+	}
+	if err := uc.validateSeason(ctx, query.Season); err != nil {
+		return domain.PlayerStats{}, err
+	}
+	stats, err := uc.StatsRepository.PlayerStats(ctx, id, query)
+	if err != nil {
+		logging.FromContext(ctx, uc.logger).Error("Error computing player stats", zap.Error(err))
+		return domain.PlayerStats{}, err
+	}
+	return stats, nil
+}
+
+// validateSeason reports a season filter naming a season that does not
+// exist; 0 means no filter.
+func (uc StatsUseCase) validateSeason(ctx context.Context, id int) error {
+	if id == 0 {
+		return nil
+	}
+	if _, err := uc.SeasonRepository.GetByID(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewFieldError("season", "season %d does not exist", id)
+		}
+		logging.FromContext(ctx, uc.logger).Error("Error fetching season", zap.Error(err))
+		return err
+	}
+	return nil
+}